@@ -7,6 +7,9 @@ require (
 	github.com/spf13/cobra v1.6.1
 	github.com/spf13/pflag v1.0.5
 	github.com/tdewolff/minify v2.3.6+incompatible
+	github.com/yuin/goldmark v1.5.6
+	golang.org/x/text v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,136 @@
+package library
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Clean Options
+type CleanOptions struct {
+	EmptyFiles  bool
+	EmptyDirs   bool
+	BrokenLinks bool
+	DryRun      bool
+	Exclude     []string
+}
+
+// Clean Report
+type CleanReport struct {
+	EmptyFiles  []string `json:"empty_files"`
+	EmptyDirs   []string `json:"empty_dirs"`
+	BrokenLinks []string `json:"broken_links"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// Find and remove zero-byte files, empty directories and dangling symlinks under root.
+// Directories are processed bottom-up so nested empty directories collapse, the root is kept.
+func CleanPath(root string, options CleanOptions) (CleanReport, error) {
+	if root == "" {
+		CurrentDirectory, _ := os.Getwd()
+		root = CurrentDirectory
+	}
+
+	report := CleanReport{EmptyFiles: []string{}, EmptyDirs: []string{}, BrokenLinks: []string{}}
+	filter := FileFilter{Exclude: options.Exclude}
+	removed := make(map[string]bool)
+	var directories []string
+
+	// Remove an entry, failures are reported as errors and the entry is not listed
+	remove := func(path string, entries *[]string) {
+		if !options.DryRun {
+			if err := os.Remove(path); err != nil {
+				report.Errors = append(report.Errors, err.Error())
+				return
+			}
+		}
+		removed[path] = true
+		*entries = append(*entries, path)
+	}
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+			return nil
+		}
+		if path != root && filter.Excluded(path) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			if _, err := os.Stat(path); err != nil && options.BrokenLinks {
+				remove(path, &report.BrokenLinks)
+			}
+		case info.IsDir():
+			if path != root {
+				directories = append(directories, path)
+			}
+		case info.Mode().IsRegular():
+			if info.Size() == 0 && options.EmptyFiles {
+				remove(path, &report.EmptyFiles)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	// Walk order is lexical, so children always come after their parent
+	if options.EmptyDirs {
+		for i := len(directories) - 1; i >= 0; i-- {
+			entries, err := os.ReadDir(directories[i])
+			if err != nil {
+				report.Errors = append(report.Errors, err.Error())
+				continue
+			}
+			empty := true
+			for _, entry := range entries {
+				if !removed[filepath.Join(directories[i], entry.Name())] {
+					empty = false
+					break
+				}
+			}
+			if empty {
+				remove(directories[i], &report.EmptyDirs)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Print Clean Report
+func PrintCleanReport(report CleanReport, format string, dryRun bool) {
+	if format == "json" {
+		PrintJSON(report)
+		return
+	}
+
+	action := "Removed"
+	if dryRun {
+		action = "Would remove"
+	}
+	for _, path := range report.EmptyFiles {
+		fmt.Println(action, "empty file:", path)
+	}
+	for _, path := range report.EmptyDirs {
+		fmt.Println(action, "empty directory:", path)
+	}
+	for _, path := range report.BrokenLinks {
+		fmt.Println(action, "broken symlink:", path)
+	}
+	for _, err := range report.Errors {
+		fmt.Println("❌", err)
+	}
+
+	if dryRun {
+		fmt.Printf("🔍 Dry run completed. Found %d empty files, %d empty directories and %d broken symlinks.\n", len(report.EmptyFiles), len(report.EmptyDirs), len(report.BrokenLinks))
+	} else {
+		fmt.Printf("✅ Successfully removed %d empty files, %d empty directories and %d broken symlinks\n", len(report.EmptyFiles), len(report.EmptyDirs), len(report.BrokenLinks))
+	}
+}
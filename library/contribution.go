@@ -1,72 +1,347 @@
 package library
 
 import (
-	"bufio"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Date format used in the markdown files
 const dateFormat = "2006-01-02" // Default date format
 
+// Default contribution entry pattern: "- [[Name]]: 2006-01-02"
+const contributorPattern = `- \[\[(?P<contributor>.*?)\]\]: (?P<date>\d{4}-\d{2}-\d{2})`
+
+// Contribution Config, read from contribution.json in the current directory or --config
+type ContributionConfig struct {
+	// Line patterns with named groups: contributor and date are required, project and hours are optional
+	Patterns    []string `json:"patterns"`
+	DateLayouts []string `json:"date_layouts"` // Go time layouts tried in order
+
+	// Field names of front matter and column names of Markdown tables, disabled when contributor is empty
+	FrontMatter ContributionFields `json:"front_matter"`
+	Table       ContributionFields `json:"table"`
+
+	Heatmap ContributionHeatmapConfig `json:"heatmap"`
+}
+
+// Contribution Fields maps entry fields to front matter fields or table columns
+type ContributionFields struct {
+	Contributor string `json:"contributor"`
+	Date        string `json:"date"`
+	Project     string `json:"project"`
+	Hours       string `json:"hours"`
+}
+
+// Contribution Entry
+type ContributionEntry struct {
+	Contributor string    `json:"contributor"`
+	Date        time.Time `json:"date"`
+	Project     string    `json:"project,omitempty"`
+	Hours       float64   `json:"hours,omitempty"`
+	File        string    `json:"file"`
+	Line        int       `json:"line"`
+}
+
+// Contributor Stats for a date range
+type ContributorStats struct {
+	Contributor string         `json:"contributor"`
+	Total       int            `json:"total"`
+	Files       int            `json:"files"`
+	Hours       float64        `json:"hours,omitempty"`
+	Projects    []string       `json:"projects,omitempty"`
+	Buckets     map[string]int `json:"buckets,omitempty"`
+}
+
+// Contribution Report
+type ContributionReport struct {
+	Start        string             `json:"start"`
+	End          string             `json:"end"`
+	Bucket       string             `json:"bucket,omitempty"`
+	Buckets      []string           `json:"buckets,omitempty"`
+	Contributors []ContributorStats `json:"contributors"`
+}
+
 // Initiate Contribution Function
 func InitiateContribution(flags Flag) {
 	// Calculate contributions in file date range
 	if *flags.Contribution {
-		count, err := calculateContributions(*flags.Path, *flags.Text, *flags.DateStart, *flags.DateEnd, *flags.Exclude)
+		// Get the Monday-Sunday range of last week by default
+		startOfLastWeek, endOfLastWeek := getLastWeekRange()
+		if *flags.Heatmap {
+			// The last 52 weeks up to today for heatmaps
+			endOfLastWeek = time.Now()
+			startOfLastWeek = endOfLastWeek.AddDate(0, 0, -52*7+1)
+		}
+		start := parseDateOr(*flags.DateStart, startOfLastWeek)
+		end := parseDateOr(*flags.DateEnd, endOfLastWeek)
+
+		// Code contributions of the repositories, the --path notes are combined when --repo is set
+		var authors []GitAuthorStats
+		if *flags.Git && !*flags.Heatmap {
+			repos := *flags.Repo
+			if len(repos) == 0 {
+				repos = []string{*flags.Path}
+				if *flags.Path == "" {
+					repos = []string{"."}
+				}
+			}
+			var err error
+			authors, err = GitContributions(repos, start, end)
+			if err != nil {
+				fmt.Println("❌ Error reading git history:", err)
+				return
+			}
+			if *flags.Text != "" {
+				var filtered []GitAuthorStats
+				for _, author := range authors {
+					if strings.Contains(author.Author, *flags.Text) || strings.Contains(author.Email, *flags.Text) {
+						filtered = append(filtered, author)
+					}
+				}
+				authors = filtered
+			}
+			if len(*flags.Repo) == 0 {
+				PrintGitContributions(authors, *flags.Format)
+				return
+			}
+		}
+
+		config, err := ReadContributionConfig(*flags.Config)
+		if err != nil {
+			fmt.Println("❌", err)
+			return
+		}
+		entries, warnings, err := ParseContributions(*flags.Path, *flags.Exclude, config)
 		if err != nil {
 			fmt.Println("❌ Error calculating contributions:", err)
 			return
 		}
+		for _, warning := range warnings {
+			fmt.Fprintln(os.Stderr, "⚠️", warning)
+		}
+		if *flags.Text != "" {
+			var filtered []ContributionEntry
+			for _, entry := range entries {
+				if strings.Contains(entry.Contributor, *flags.Text) {
+					filtered = append(filtered, entry)
+				}
+			}
+			entries = filtered
+		}
+
+		if *flags.Heatmap {
+			heatmapConfig := config.Heatmap
+			if *flags.Thresholds != "" {
+				heatmapConfig.Thresholds, err = ParseHeatmapThresholds(*flags.Thresholds)
+				if err != nil {
+					fmt.Println("❌", err)
+					return
+				}
+			}
+			if *flags.WeekStart != "" {
+				heatmapConfig.WeekStart = *flags.WeekStart
+			}
+			heatmap, err := NewContributionHeatmap(entries, start, end, heatmapConfig)
+			if err != nil {
+				fmt.Println("❌", err)
+				return
+			}
+			title := "Contributions"
+			if *flags.Text != "" {
+				title = "Contributions of " + *flags.Text
+			}
+			PrintContributionHeatmap(heatmap, title, *flags.Format, *flags.Output)
+			return
+		}
+
+		report, err := NewContributionReport(entries, start, end, *flags.Bucket)
+		if err != nil {
+			fmt.Println("❌", err)
+			return
+		}
+		if !*flags.Git {
+			PrintContributionReport(report, *flags.Format)
+			return
+		}
 
-		fmt.Println("🐙 There are", count, "files containing", *flags.Text)
+		// Combined report of notes and code contributions
+		switch *flags.Format {
+		case "json":
+			if authors == nil {
+				authors = []GitAuthorStats{}
+			}
+			if report.Contributors == nil {
+				report.Contributors = []ContributorStats{}
+			}
+			PrintJSON(map[string]interface{}{"notes": report, "git": authors})
+		case "csv":
+			PrintContributionReport(report, *flags.Format)
+			fmt.Println()
+			PrintGitContributions(authors, *flags.Format)
+		default:
+			fmt.Println("📝 Notes")
+			PrintContributionReport(report, *flags.Format)
+			fmt.Println()
+			fmt.Println("💻 Code")
+			PrintGitContributions(authors, *flags.Format)
+		}
 	}
 }
 
 // Calculate the date range (Monday-Sunday of the previous week)
 func getLastWeekRange() (time.Time, time.Time) {
 	now := time.Now()
-	weekday := now.Weekday()
 
-	// Calculate the previous Sunday's date
-	daysSinceSunday := int(weekday+1) % 7
-	sundayLastWeek := now.AddDate(0, 0, -daysSinceSunday)
+	// Days since this week's Monday
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+	mondayThisWeek := now.AddDate(0, 0, -daysSinceMonday)
 
-	// Calculate the previous Monday's date
-	mondayLastWeek := sundayLastWeek.AddDate(0, 0, -5)
+	// Previous week's Monday and Sunday
+	mondayLastWeek := mondayThisWeek.AddDate(0, 0, -7)
+	sundayLastWeek := mondayThisWeek.AddDate(0, 0, -1)
 
 	return mondayLastWeek, sundayLastWeek
 }
 
-// Function to traverse the markdown directory and aggregate contributions
-func calculateContributions(dirPath string, text string, start string, end string, exclude []string) (int, error) {
-	var count int
-	contributorPattern := regexp.MustCompile(`- \[\[(.*?)\]\]: (\d{4}-\d{2}-\d{2})`)
+// Parse a date in the date format, returning the fallback when empty or invalid
+func parseDateOr(value string, fallback time.Time) time.Time {
+	date, err := time.Parse(dateFormat, value)
+	if err != nil {
+		return fallback
+	}
+	return date
+}
+
+// Check if a date is between start and end by day, both inclusive. Zero bounds are open.
+func dateInRange(date time.Time, start time.Time, end time.Time) bool {
+	day := date.Format(dateFormat)
+	if !start.IsZero() && day < start.Format(dateFormat) {
+		return false
+	}
+	if !end.IsZero() && day > end.Format(dateFormat) {
+		return false
+	}
+	return true
+}
 
-	// Get the Monday-Sunday range of last week
-	startOfLastWeek, endOfLastWeek := getLastWeekRange()
+// Default Contribution Config
+func DefaultContributionConfig() ContributionConfig {
+	return ContributionConfig{Patterns: []string{contributorPattern}, DateLayouts: []string{dateFormat}}
+}
 
-	// Check if a start date is provided
-	startDate, err := time.Parse(dateFormat, start)
+// Read the contribution config, missing fields keep their defaults. A missing default config file is not an error.
+func ReadContributionConfig(path string) (ContributionConfig, error) {
+	config := DefaultContributionConfig()
+	explicit := path != ""
+	if !explicit {
+		path = "contribution.json"
+	}
+
+	data, err := os.ReadFile(path)
 	if err != nil {
-		startDate = startOfLastWeek
+		if !explicit && os.IsNotExist(err) {
+			return config, nil
+		}
+		return config, err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	if len(config.DateLayouts) == 0 {
+		config.DateLayouts = []string{dateFormat}
+	}
+	return config, nil
+}
+
+// Compile the line patterns, each must have contributor and date groups
+func (c ContributionConfig) compilePatterns() ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for _, pattern := range c.Patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %v", pattern, err)
+		}
+		if compiled.SubexpIndex("contributor") < 0 || compiled.SubexpIndex("date") < 0 {
+			return nil, fmt.Errorf("pattern %s needs (?P<contributor>...) and (?P<date>...) groups", pattern)
+		}
+		patterns = append(patterns, compiled)
+	}
+	return patterns, nil
+}
+
+// Parse a date with the configured layouts
+func (c ContributionConfig) parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range c.DateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
 	}
+	return time.Time{}, fmt.Errorf("malformed date %q", value)
+}
 
-	// Check if an end date is provided
-	endDate, err := time.Parse(dateFormat, end)
+// Contributor name without wikilink brackets and alias: "[[Alice|A]]" is "Alice"
+func cleanContributor(name string) string {
+	name = strings.TrimSpace(name)
+	if strings.HasPrefix(name, "[[") && strings.HasSuffix(name, "]]") {
+		name = strings.TrimSuffix(strings.TrimPrefix(name, "[["), "]]")
+		if pipe := strings.Index(name, "|"); pipe >= 0 {
+			name = name[:pipe]
+		}
+	}
+	return strings.TrimSpace(name)
+}
+
+// Parse every contribution entry of the files in a directory from line patterns, front matter and tables.
+// Malformed entries are returned as warnings and do not stop the walk.
+func ParseContributions(dirPath string, exclude []string, config ContributionConfig) ([]ContributionEntry, []string, error) {
+	patterns, err := config.compilePatterns()
 	if err != nil {
-		endDate = endOfLastWeek
+		return nil, nil, err
+	}
+
+	var entries []ContributionEntry
+	var warnings []string
+	warn := func(path string, line int, format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf("%s:%d: ", path, line)+fmt.Sprintf(format, args...))
+	}
+
+	// Entry from raw values, hours and project are optional
+	add := func(path string, line int, contributor string, date string, project string, hours string) {
+		contributor = cleanContributor(contributor)
+		if contributor == "" {
+			return
+		}
+		parsed, err := config.parseDate(date)
+		if err != nil {
+			warn(path, line, "%v for %s", err, contributor)
+			return
+		}
+		entry := ContributionEntry{Contributor: contributor, Date: parsed, Project: strings.TrimSpace(project), File: path, Line: line}
+		if strings.TrimSpace(hours) != "" {
+			entry.Hours, err = strconv.ParseFloat(strings.TrimSpace(hours), 64)
+			if err != nil {
+				warn(path, line, "malformed hours %q for %s", hours, contributor)
+			}
+		}
+		entries = append(entries, entry)
 	}
 
 	// Walk through the directories in the provided path
 	err = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			fmt.Println("❌ Error:", err)
-			return err
+			warn(path, 0, "%v", err)
+			return nil
 		}
 
 		// Check if the directory should be ignored
@@ -76,37 +351,202 @@ func calculateContributions(dirPath string, text string, start string, end strin
 			}
 		}
 
-		if !info.IsDir() {
-			file, err := os.Open(path)
-			if err != nil {
-				return err
-			}
-			defer file.Close()
-
-			scanner := bufio.NewScanner(file)
-			for scanner.Scan() {
-				// Check if the line contains the contributor's name
-				line := scanner.Text()
-				if strings.Contains(line, text) {
-					if match := contributorPattern.FindStringSubmatch(line); match != nil {
-						dateStr := match[2]
-						contributionDate, err := time.Parse(dateFormat, dateStr)
-						if err != nil {
-							return err
-						}
+		if info.IsDir() {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			warn(path, 0, "%v", err)
+			return nil
+		}
+		lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
 
-						// Only include contributions within the Monday-Sunday of last week
-						if contributionDate.After(startDate.AddDate(0, 0, -1)) && contributionDate.Before(endDate.AddDate(0, 0, 1)) {
-							count++
-							break
+		// Line patterns
+		for i, line := range lines {
+			for _, pattern := range patterns {
+				for _, match := range pattern.FindAllStringSubmatch(line, -1) {
+					group := func(name string) string {
+						if index := pattern.SubexpIndex(name); index >= 0 {
+							return match[index]
 						}
+						return ""
 					}
+					add(path, i+1, group("contributor"), group("date"), group("project"), group("hours"))
+				}
+			}
+		}
+
+		if !IsMarkdownFile(path) {
+			return nil
+		}
+
+		// Front matter fields, the contributor may be a list
+		if fields := config.FrontMatter; fields.Contributor != "" {
+			frontMatter, _ := MarkdownFrontMatter(string(content))
+			var values map[string]interface{}
+			if err := yaml.Unmarshal([]byte(frontMatter), &values); err != nil {
+				warn(path, 1, "invalid front matter: %v", err)
+			} else if values[fields.Contributor] != nil {
+				note := MarkdownNote{File: path, Fields: values}
+				date := strings.Join(note.Values(fields.Date), "")
+				project := strings.Join(note.Values(fields.Project), ", ")
+				hours := strings.Join(note.Values(fields.Hours), "")
+				for _, contributor := range note.Values(fields.Contributor) {
+					add(path, 1, contributor, date, project, hours)
 				}
 			}
 		}
 
+		// Markdown table rows with contributor and date columns
+		if fields := config.Table; fields.Contributor != "" {
+			code := MarkdownCodeLines(lines)
+			for i := 0; i+1 < len(lines); i++ {
+				if code[i] || !markdownTableRowPattern.MatchString(lines[i]) || !markdownTableRulePattern.MatchString(lines[i+1]) {
+					continue
+				}
+				columns := make(map[string]int)
+				for c, header := range splitMarkdownTableRow(lines[i]) {
+					columns[strings.ToLower(header)] = c
+				}
+				cell := func(row []string, name string) string {
+					if c, ok := columns[strings.ToLower(name)]; ok && name != "" && c < len(row) {
+						return row[c]
+					}
+					return ""
+				}
+				_, hasContributor := columns[strings.ToLower(fields.Contributor)]
+				_, hasDate := columns[strings.ToLower(fields.Date)]
+				j := i + 2
+				for ; j < len(lines) && !code[j] && strings.Contains(lines[j], "|"); j++ {
+					if hasContributor && hasDate {
+						row := splitMarkdownTableRow(lines[j])
+						add(path, j+1, cell(row, fields.Contributor), cell(row, fields.Date), cell(row, fields.Project), cell(row, fields.Hours))
+					}
+				}
+				i = j - 1
+			}
+		}
 		return nil
 	})
 
-	return count, err
+	return entries, warnings, err
+}
+
+// Bucket key of a date: day (2006-01-02), week (ISO, 2006-W01) or month (2006-01)
+func contributionBucket(date time.Time, bucket string) string {
+	switch bucket {
+	case "day":
+		return date.Format(dateFormat)
+	case "week":
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case "month":
+		return date.Format("2006-01")
+	}
+	return ""
+}
+
+// Build a contribution report of the entries between start and end, ranked by total
+func NewContributionReport(entries []ContributionEntry, start time.Time, end time.Time, bucket string) (ContributionReport, error) {
+	if bucket != "" && bucket != "day" && bucket != "week" && bucket != "month" {
+		return ContributionReport{}, fmt.Errorf("unsupported bucket: %s (day|week|month)", bucket)
+	}
+	report := ContributionReport{Start: start.Format(dateFormat), End: end.Format(dateFormat), Bucket: bucket}
+
+	// Every bucket of the range, so empty periods are shown
+	if bucket != "" {
+		for day := start; day.Format(dateFormat) <= report.End; day = day.AddDate(0, 0, 1) {
+			if key := contributionBucket(day, bucket); len(report.Buckets) == 0 || report.Buckets[len(report.Buckets)-1] != key {
+				report.Buckets = append(report.Buckets, key)
+			}
+		}
+	}
+
+	stats := make(map[string]*ContributorStats)
+	files := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		if !dateInRange(entry.Date, start, end) {
+			continue
+		}
+		contributor, exists := stats[entry.Contributor]
+		if !exists {
+			contributor = &ContributorStats{Contributor: entry.Contributor}
+			if bucket != "" {
+				contributor.Buckets = make(map[string]int)
+			}
+			stats[entry.Contributor] = contributor
+			files[entry.Contributor] = make(map[string]bool)
+		}
+		contributor.Total++
+		contributor.Hours += entry.Hours
+		if entry.Project != "" && !SliceContainsString(contributor.Projects, entry.Project) {
+			contributor.Projects = append(contributor.Projects, entry.Project)
+		}
+		files[entry.Contributor][entry.File] = true
+		if bucket != "" {
+			contributor.Buckets[contributionBucket(entry.Date, bucket)]++
+		}
+	}
+
+	for name, contributor := range stats {
+		contributor.Files = len(files[name])
+		report.Contributors = append(report.Contributors, *contributor)
+	}
+	sort.Slice(report.Contributors, func(i, j int) bool {
+		if report.Contributors[i].Total != report.Contributors[j].Total {
+			return report.Contributors[i].Total > report.Contributors[j].Total
+		}
+		return report.Contributors[i].Contributor < report.Contributors[j].Contributor
+	})
+	return report, nil
+}
+
+// Rows of the report: rank, contributor, total, files, hours when tracked and one column per bucket
+func (r ContributionReport) rows() ([]string, [][]string) {
+	hours := false
+	for _, contributor := range r.Contributors {
+		hours = hours || contributor.Hours != 0
+	}
+
+	headers := []string{"RANK", "CONTRIBUTOR", "TOTAL", "FILES"}
+	if hours {
+		headers = append(headers, "HOURS")
+	}
+	headers = append(headers, r.Buckets...)
+	var rows [][]string
+	for i, contributor := range r.Contributors {
+		row := []string{strconv.Itoa(i + 1), contributor.Contributor, strconv.Itoa(contributor.Total), strconv.Itoa(contributor.Files)}
+		if hours {
+			row = append(row, strconv.FormatFloat(contributor.Hours, 'f', -1, 64))
+		}
+		for _, bucket := range r.Buckets {
+			row = append(row, strconv.Itoa(contributor.Buckets[bucket]))
+		}
+		rows = append(rows, row)
+	}
+	return headers, rows
+}
+
+// Print Contribution Report as table, csv or json
+func PrintContributionReport(report ContributionReport, format string) {
+	switch format {
+	case "json":
+		if report.Contributors == nil {
+			report.Contributors = []ContributorStats{}
+		}
+		PrintJSON(report)
+	case "csv":
+		headers, rows := report.rows()
+		writer := csv.NewWriter(os.Stdout)
+		writer.Write(headers)
+		writer.WriteAll(rows)
+	default:
+		headers, rows := report.rows()
+		PrintTable(headers, rows)
+		total := 0
+		for _, contributor := range report.Contributors {
+			total += contributor.Total
+		}
+		fmt.Println("🐙 There are", total, "contributions by", len(report.Contributors), "contributors from", report.Start, "to", report.End)
+	}
 }
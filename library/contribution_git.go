@@ -0,0 +1,141 @@
+package library
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Git Author Stats across one or more repositories
+type GitAuthorStats struct {
+	Author  string   `json:"author"`
+	Email   string   `json:"email"`
+	Commits int      `json:"commits"`
+	Added   int      `json:"added"`
+	Removed int      `json:"removed"`
+	Files   int      `json:"files"`
+	Repos   []string `json:"repos"`
+}
+
+// Field and record separators of the git log format
+const (
+	gitLogRecord = "\x1e"
+	gitLogField  = "\x1f"
+)
+
+// Aggregate commits, added and removed lines and touched files per author from git log --numstat.
+// Authors are mapped with .mailmap and grouped by email.
+func GitContributions(repos []string, start time.Time, end time.Time) ([]GitAuthorStats, error) {
+	stats := make(map[string]*GitAuthorStats)
+	files := make(map[string]map[string]bool)
+
+	for _, repo := range repos {
+		args := []string{"-C", repo, "log", "--no-merges", "--numstat", "--use-mailmap", "--date=short",
+			"--pretty=format:" + gitLogRecord + "%aN" + gitLogField + "%aE" + gitLogField + "%ad"}
+
+		var stderr bytes.Buffer
+		cmd := exec.Command("git", args...)
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("git log in %s: %v %s", repo, err, strings.TrimSpace(stderr.String()))
+		}
+
+		name := filepath.Base(filepath.Clean(repo))
+		if absolute, err := filepath.Abs(repo); err == nil {
+			name = filepath.Base(absolute)
+		}
+
+		for _, record := range strings.Split(string(output), gitLogRecord) {
+			scanner := bufio.NewScanner(strings.NewReader(record))
+			if !scanner.Scan() {
+				continue
+			}
+			header := strings.Split(scanner.Text(), gitLogField)
+			if len(header) != 3 {
+				continue
+			}
+
+			// The range applies to the author date, git's --since and --until would filter by committer date and time of day
+			date, err := time.Parse(dateFormat, header[2])
+			if err != nil || !dateInRange(date, start, end) {
+				continue
+			}
+
+			key := strings.ToLower(header[1])
+			author, exists := stats[key]
+			if !exists {
+				author = &GitAuthorStats{Author: header[0], Email: header[1]}
+				stats[key] = author
+				files[key] = make(map[string]bool)
+			}
+			author.Commits++
+			if !SliceContainsString(author.Repos, name) {
+				author.Repos = append(author.Repos, name)
+			}
+
+			// Numstat lines: added, removed and path, binary files use "-"
+			for scanner.Scan() {
+				fields := strings.SplitN(scanner.Text(), "\t", 3)
+				if len(fields) != 3 {
+					continue
+				}
+				added, _ := strconv.Atoi(fields[0])
+				removed, _ := strconv.Atoi(fields[1])
+				author.Added += added
+				author.Removed += removed
+				files[key][name+"/"+fields[2]] = true
+			}
+		}
+	}
+
+	var result []GitAuthorStats
+	for key, author := range stats {
+		author.Files = len(files[key])
+		result = append(result, *author)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Commits != result[j].Commits {
+			return result[i].Commits > result[j].Commits
+		}
+		return result[i].Author < result[j].Author
+	})
+	return result, nil
+}
+
+// Print Git Contributions as table, csv or json
+func PrintGitContributions(stats []GitAuthorStats, format string) {
+	headers := []string{"RANK", "AUTHOR", "EMAIL", "COMMITS", "ADDED", "REMOVED", "FILES", "REPOS"}
+	var rows [][]string
+	for i, author := range stats {
+		rows = append(rows, []string{strconv.Itoa(i + 1), author.Author, author.Email, strconv.Itoa(author.Commits), strconv.Itoa(author.Added),
+			strconv.Itoa(author.Removed), strconv.Itoa(author.Files), strings.Join(author.Repos, ",")})
+	}
+
+	switch format {
+	case "json":
+		if stats == nil {
+			stats = []GitAuthorStats{}
+		}
+		PrintJSON(stats)
+	case "csv":
+		writer := csv.NewWriter(os.Stdout)
+		writer.Write(headers)
+		writer.WriteAll(rows)
+	default:
+		PrintTable(headers, rows)
+		commits := 0
+		for _, author := range stats {
+			commits += author.Commits
+		}
+		fmt.Println("🐙 There are", commits, "commits by", len(stats), "authors")
+	}
+}
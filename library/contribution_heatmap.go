@@ -0,0 +1,335 @@
+package library
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Contribution Heatmap Config, the "heatmap" field of contribution.json
+type ContributionHeatmapConfig struct {
+	Thresholds []int    `json:"thresholds"` // Minimum contributions of each level above zero, ascending
+	Colors     []string `json:"colors"`     // One color per level, the first is for days without contributions
+	WeekStart  string   `json:"week_start"` // sunday (default) or monday
+}
+
+// Contribution Heatmap of contributions per day
+type ContributionHeatmap struct {
+	Start     time.Time
+	End       time.Time
+	WeekStart time.Weekday
+	Days      map[string]int
+	Total     int
+	Max       int
+	Config    ContributionHeatmapConfig
+}
+
+// Default heatmap levels and GitHub colors
+func DefaultContributionHeatmapConfig() ContributionHeatmapConfig {
+	return ContributionHeatmapConfig{
+		Thresholds: []int{1, 3, 6, 10},
+		Colors:     []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"},
+		WeekStart:  "sunday",
+	}
+}
+
+// Terminal shades of the levels
+var heatmapShades = []string{"·", "░", "▒", "▓", "█"}
+
+// Heatmap cell size and gap in pixels
+const (
+	heatmapCell = 11
+	heatmapGap  = 3
+)
+
+// Create a heatmap of the entries between start and end, both inclusive
+func NewContributionHeatmap(entries []ContributionEntry, start time.Time, end time.Time, config ContributionHeatmapConfig) (ContributionHeatmap, error) {
+	defaults := DefaultContributionHeatmapConfig()
+	if len(config.Thresholds) == 0 {
+		config.Thresholds = defaults.Thresholds
+	}
+	for i := 1; i < len(config.Thresholds); i++ {
+		if config.Thresholds[i] <= config.Thresholds[i-1] {
+			return ContributionHeatmap{}, fmt.Errorf("heatmap thresholds must be ascending: %v", config.Thresholds)
+		}
+	}
+	if len(config.Colors) == 0 {
+		config.Colors = heatmapPalette(defaults.Colors, len(config.Thresholds))
+	}
+	if len(config.Colors) != len(config.Thresholds)+1 {
+		return ContributionHeatmap{}, fmt.Errorf("heatmap needs %d colors for %d thresholds, got %d", len(config.Thresholds)+1, len(config.Thresholds), len(config.Colors))
+	}
+
+	heatmap := ContributionHeatmap{
+		Start:  time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC),
+		End:    time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC),
+		Days:   make(map[string]int),
+		Config: config,
+	}
+	switch strings.ToLower(config.WeekStart) {
+	case "", "sunday":
+		heatmap.WeekStart = time.Sunday
+	case "monday":
+		heatmap.WeekStart = time.Monday
+	default:
+		return ContributionHeatmap{}, fmt.Errorf("unsupported week start: %s (sunday|monday)", config.WeekStart)
+	}
+
+	for _, entry := range entries {
+		if !dateInRange(entry.Date, start, end) {
+			continue
+		}
+		day := entry.Date.Format(dateFormat)
+		heatmap.Days[day]++
+		heatmap.Total++
+		if heatmap.Days[day] > heatmap.Max {
+			heatmap.Max = heatmap.Days[day]
+		}
+	}
+	return heatmap, nil
+}
+
+// Palette for a number of levels, the colors between the first and last level color are interpolated
+func heatmapPalette(colors []string, levels int) []string {
+	if levels == len(colors)-1 {
+		return colors
+	}
+	var low, high [3]int64
+	fmt.Sscanf(colors[1], "#%02x%02x%02x", &low[0], &low[1], &low[2])
+	fmt.Sscanf(colors[len(colors)-1], "#%02x%02x%02x", &high[0], &high[1], &high[2])
+
+	palette := []string{colors[0]}
+	for level := 0; level < levels; level++ {
+		var rgb [3]int64
+		for i := range rgb {
+			rgb[i] = low[i]
+			if levels > 1 {
+				rgb[i] += (high[i] - low[i]) * int64(level) / int64(levels-1)
+			}
+		}
+		palette = append(palette, fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2]))
+	}
+	return palette
+}
+
+// Level of a day count, 0 for no contributions
+func (h ContributionHeatmap) Level(count int) int {
+	level := 0
+	for i, threshold := range h.Config.Thresholds {
+		if count >= threshold {
+			level = i + 1
+		}
+	}
+	return level
+}
+
+// Weeks of the heatmap as columns of seven days starting at the week start, days outside the range are zero
+func (h ContributionHeatmap) Weeks() [][7]time.Time {
+	first := h.Start.AddDate(0, 0, -((int(h.Start.Weekday()) - int(h.WeekStart) + 7) % 7))
+
+	var weeks [][7]time.Time
+	for day := first; !day.After(h.End); day = day.AddDate(0, 0, 7) {
+		var week [7]time.Time
+		for i := range week {
+			date := day.AddDate(0, 0, i)
+			if !date.Before(h.Start) && !date.After(h.End) {
+				week[i] = date
+			}
+		}
+		weeks = append(weeks, week)
+	}
+	return weeks
+}
+
+// Weekday label of a row
+func (h ContributionHeatmap) weekday(row int) string {
+	return time.Weekday((int(h.WeekStart) + row) % 7).String()[:3]
+}
+
+// Month labels by week column, set on the first week of each month
+func (h ContributionHeatmap) months(weeks [][7]time.Time) []string {
+	labels := make([]string, len(weeks))
+	previous := -1
+	for i, week := range weeks {
+		for _, date := range week {
+			if !date.IsZero() {
+				if int(date.Month()) != previous {
+					labels[i] = date.Month().String()[:3]
+					previous = int(date.Month())
+				}
+				break
+			}
+		}
+	}
+	return labels
+}
+
+// Render the heatmap for the terminal with one shade per level
+func (h ContributionHeatmap) Terminal() string {
+	weeks := h.Weeks()
+	var output strings.Builder
+
+	// Month header, labels are skipped when they would overlap
+	header := []rune(strings.Repeat(" ", 4+len(weeks)*2))
+	for i, label := range h.months(weeks) {
+		position := 4 + i*2
+		if label == "" || position+len(label) > len(header) {
+			continue
+		}
+		if position > 4 && header[position-1] != ' ' {
+			continue
+		}
+		copy(header[position:], []rune(label))
+	}
+	output.WriteString(strings.TrimRight(string(header), " ") + "\n")
+
+	for row := 0; row < 7; row++ {
+		label := "    "
+		if row%2 == 1 {
+			label = h.weekday(row) + " "
+		}
+		line := label
+		for _, week := range weeks {
+			if week[row].IsZero() {
+				line += "  "
+				continue
+			}
+			line += heatmapShades[h.shade(h.Days[week[row].Format(dateFormat)])] + " "
+		}
+		output.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+
+	output.WriteString("\nLess ")
+	for level := range h.Config.Colors {
+		output.WriteString(heatmapShades[h.shadeOf(level)] + " ")
+	}
+	output.WriteString("More\n")
+	return output.String()
+}
+
+// Terminal shade of a count, levels are spread over the available shades
+func (h ContributionHeatmap) shade(count int) int {
+	return h.shadeOf(h.Level(count))
+}
+
+// Terminal shade of a level
+func (h ContributionHeatmap) shadeOf(level int) int {
+	if level == 0 {
+		return 0
+	}
+	if len(h.Config.Thresholds) == 1 {
+		return len(heatmapShades) - 1
+	}
+	return 1 + (level-1)*(len(heatmapShades)-2)/(len(h.Config.Thresholds)-1)
+}
+
+// Render the heatmap as a standalone SVG image
+func (h ContributionHeatmap) SVG() string {
+	weeks := h.Weeks()
+	step := heatmapCell + heatmapGap
+	left, top := 32, 20
+	width := left + len(weeks)*step + heatmapGap
+	if width < 280 {
+		width = 280 // Room for the legend
+	}
+	height := top + 7*step + 28
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="-apple-system, BlinkMacSystemFont, Segoe UI, Helvetica, Arial, sans-serif" font-size="9" fill="#57606a">`+"\n", width, height, width, height)
+
+	for i, label := range h.months(weeks) {
+		if label != "" {
+			fmt.Fprintf(&svg, `  <text x="%d" y="%d">%s</text>`+"\n", left+i*step, top-6, label)
+		}
+	}
+	for row := 1; row < 7; row += 2 {
+		fmt.Fprintf(&svg, `  <text x="0" y="%d">%s</text>`+"\n", top+row*step+heatmapCell-2, h.weekday(row))
+	}
+
+	for i, week := range weeks {
+		for row, date := range week {
+			if date.IsZero() {
+				continue
+			}
+			day := date.Format(dateFormat)
+			count := h.Days[day]
+			fmt.Fprintf(&svg, `  <rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s: %d contributions</title></rect>`+"\n",
+				left+i*step, top+row*step, heatmapCell, heatmapCell, html.EscapeString(h.Config.Colors[h.Level(count)]), day, count)
+		}
+	}
+
+	// Legend
+	legend := width - len(h.Config.Colors)*step - 60
+	y := top + 7*step + 10
+	fmt.Fprintf(&svg, `  <text x="%d" y="%d">%d contributions</text>`+"\n", left, y+heatmapCell-2, h.Total)
+	fmt.Fprintf(&svg, `  <text x="%d" y="%d">Less</text>`+"\n", legend, y+heatmapCell-2)
+	for level, color := range h.Config.Colors {
+		label := "0"
+		if level > 0 {
+			label = strconv.Itoa(h.Config.Thresholds[level-1]) + "+"
+		}
+		fmt.Fprintf(&svg, `  <rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s</title></rect>`+"\n",
+			legend+26+level*step, y, heatmapCell, heatmapCell, html.EscapeString(color), label)
+	}
+	fmt.Fprintf(&svg, `  <text x="%d" y="%d">More</text>`+"\n", legend+30+len(h.Config.Colors)*step, y+heatmapCell-2)
+	svg.WriteString("</svg>\n")
+	return svg.String()
+}
+
+// Render the heatmap as an HTML page embedding the SVG
+func (h ContributionHeatmap) HTML(title string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+</head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif;">
+<h2>%s</h2>
+<p>%s to %s</p>
+%s</body>
+</html>
+`, html.EscapeString(title), html.EscapeString(title), h.Start.Format(dateFormat), h.End.Format(dateFormat), h.SVG())
+}
+
+// Parse comma separated heatmap thresholds, e.g. "1,3,6,10"
+func ParseHeatmapThresholds(value string) ([]int, error) {
+	var thresholds []int
+	for _, part := range strings.Split(value, ",") {
+		threshold, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || threshold < 1 {
+			return nil, fmt.Errorf("invalid heatmap threshold: %q", part)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds, nil
+}
+
+// Print the heatmap for the terminal, or write it as svg or html to the output file or stdout
+func PrintContributionHeatmap(heatmap ContributionHeatmap, title string, format string, output string) {
+	var content string
+	switch format {
+	case "svg":
+		content = heatmap.SVG()
+	case "html":
+		content = heatmap.HTML(title)
+	default:
+		fmt.Println("📅", title, heatmap.Start.Format(dateFormat), "to", heatmap.End.Format(dateFormat))
+		fmt.Print(heatmap.Terminal())
+		fmt.Println("🐙 There are", heatmap.Total, "contributions on", len(heatmap.Days), "days")
+		return
+	}
+
+	if output == "" {
+		fmt.Print(content)
+		return
+	}
+	if err := os.WriteFile(output, []byte(content), 0644); err != nil {
+		fmt.Println("❌ Error writing heatmap:", err)
+		os.Exit(1)
+	}
+	fmt.Println("✅ Heatmap written to", output)
+}
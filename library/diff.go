@@ -0,0 +1,454 @@
+package library
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Directory Diff Entry
+type DirectoryDiffEntry struct {
+	Path    string `json:"path"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	SizeA   int64  `json:"size_a"`
+	SizeB   int64  `json:"size_b"`
+	ModTime string `json:"mod_time,omitempty"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+// Directory Diff Status
+const (
+	DiffAdded    = "added"
+	DiffRemoved  = "removed"
+	DiffModified = "modified"
+)
+
+// DirectoryDiff compares two directory trees by relative path, size, mtime and
+// optionally content hash. Entries are reported from the point of view of pathA,
+// so "added" means the entry only exists in pathB.
+func DirectoryDiff(pathA string, pathB string, useHash bool, exclude []string) ([]DirectoryDiffEntry, error) {
+	treeA, err := collectTree(pathA, exclude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", pathA, err)
+	}
+	treeB, err := collectTree(pathB, exclude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", pathB, err)
+	}
+
+	var entries []DirectoryDiffEntry
+	for rel, infoA := range treeA {
+		infoB, ok := treeB[rel]
+		if !ok {
+			entries = append(entries, DirectoryDiffEntry{Path: displayPath(rel, infoA), Status: DiffRemoved, SizeA: sizeOf(infoA)})
+			continue
+		}
+
+		// Type change (file <-> directory)
+		if infoA.IsDir() != infoB.IsDir() {
+			entries = append(entries, DirectoryDiffEntry{Path: rel, Status: DiffModified, Reason: "type", SizeA: sizeOf(infoA), SizeB: sizeOf(infoB)})
+			continue
+		}
+		if infoA.IsDir() {
+			continue
+		}
+
+		reason, err := compareFiles(filepath.Join(pathA, rel), filepath.Join(pathB, rel), infoA, infoB, useHash)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			entries = append(entries, DirectoryDiffEntry{
+				Path:    rel,
+				Status:  DiffModified,
+				Reason:  reason,
+				SizeA:   infoA.Size(),
+				SizeB:   infoB.Size(),
+				ModTime: infoB.ModTime().Format("2006-01-02 15:04:05"),
+			})
+		}
+	}
+	for rel, infoB := range treeB {
+		if _, ok := treeA[rel]; !ok {
+			entries = append(entries, DirectoryDiffEntry{Path: displayPath(rel, infoB), Status: DiffAdded, SizeB: sizeOf(infoB)})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+
+	return entries, nil
+}
+
+// Print Directory Diff as table or JSON, optionally with unified diff of changed text files
+func PrintDirectoryDiff(pathA string, pathB string, entries []DirectoryDiffEntry, format string, patch bool) {
+	if patch {
+		for i, entry := range entries {
+			if entry.Status != DiffModified || entry.Reason == "type" {
+				continue
+			}
+			fileA := filepath.Join(pathA, entry.Path)
+			fileB := filepath.Join(pathB, entry.Path)
+			if IsBinaryFile(fileA) || IsBinaryFile(fileB) {
+				continue
+			}
+			entries[i].Diff = UnifiedDiff(splitLines(string(ReadFile(fileA))), splitLines(string(ReadFile(fileB))), "a/"+entry.Path, "b/"+entry.Path, 3)
+		}
+	}
+
+	if format == "json" {
+		if entries == nil {
+			entries = []DirectoryDiffEntry{}
+		}
+		PrintJSON(entries)
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("✅ No differences between", pathA, "and", pathB)
+		return
+	}
+
+	var rows [][]string
+	var added, removed, modified int
+	for _, entry := range entries {
+		switch entry.Status {
+		case DiffAdded:
+			added++
+		case DiffRemoved:
+			removed++
+		case DiffModified:
+			modified++
+		}
+		rows = append(rows, []string{entry.Status, entry.Path, fmt.Sprint(entry.SizeA), fmt.Sprint(entry.SizeB), entry.Reason})
+	}
+	PrintTable([]string{"STATUS", "PATH", "SIZE A", "SIZE B", "REASON"}, rows)
+	fmt.Printf("📊 %d added, %d removed, %d modified\n", added, removed, modified)
+
+	for _, entry := range entries {
+		if entry.Diff != "" {
+			fmt.Println()
+			fmt.Print(entry.Diff)
+		}
+	}
+}
+
+// Collect all entries of a directory tree keyed by relative slash path
+func collectTree(root string, exclude []string) (map[string]os.FileInfo, error) {
+	tree := make(map[string]os.FileInfo)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+
+		// Check if the path should be ignored
+		for _, excludePath := range exclude {
+			if strings.Contains(path, excludePath) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		tree[filepath.ToSlash(rel)] = info
+		return nil
+	})
+	return tree, err
+}
+
+// Compare two files, returning the reason they differ or an empty string
+func compareFiles(fileA string, fileB string, infoA os.FileInfo, infoB os.FileInfo, useHash bool) (string, error) {
+	if infoA.Size() != infoB.Size() {
+		return "size", nil
+	}
+	if useHash {
+		hashA, err := HashFile(fileA)
+		if err != nil {
+			return "", err
+		}
+		hashB, err := HashFile(fileB)
+		if err != nil {
+			return "", err
+		}
+		if hashA != hashB {
+			return "content", nil
+		}
+		return "", nil
+	}
+	if !infoA.ModTime().Equal(infoB.ModTime()) {
+		return "mtime", nil
+	}
+	return "", nil
+}
+
+// HashFile returns the hex encoded SHA-256 of a file
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Split text into lines without the trailing empty line
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// Display directories with a trailing slash
+func displayPath(rel string, info os.FileInfo) string {
+	if info.IsDir() {
+		return rel + "/"
+	}
+	return rel
+}
+
+// Size of a file, directories are reported as zero
+func sizeOf(info os.FileInfo) int64 {
+	if info.IsDir() {
+		return 0
+	}
+	return info.Size()
+}
+
+// Diff operation kind
+type diffOp struct {
+	Kind byte // ' ', '-', '+'
+	Line string
+}
+
+// UnifiedDiff returns a unified diff between two sets of lines using the Myers algorithm
+func UnifiedDiff(a []string, b []string, nameA string, nameB string, context int) string {
+	ops := myersDiff(a, b)
+
+	// Nothing changed
+	changed := false
+	for _, op := range ops {
+		if op.Kind != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+
+	// Group operations into hunks separated by more than 2*context unchanged lines
+	i := 0
+	for i < len(ops) {
+		// Find next change
+		for i < len(ops) && ops[i].Kind == ' ' {
+			i++
+		}
+		if i >= len(ops) {
+			break
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		// Extend until a run of unchanged lines longer than 2*context
+		end := i
+		for end < len(ops) {
+			if ops[end].Kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].Kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end += context
+				if end > len(ops) {
+					end = len(ops)
+				}
+				break
+			}
+			end = run
+		}
+
+		// Compute hunk header line numbers
+		lineA, lineB := 1, 1
+		for _, op := range ops[:start] {
+			if op.Kind != '+' {
+				lineA++
+			}
+			if op.Kind != '-' {
+				lineB++
+			}
+		}
+		countA, countB := 0, 0
+		for _, op := range ops[start:end] {
+			if op.Kind != '+' {
+				countA++
+			}
+			if op.Kind != '-' {
+				countB++
+			}
+		}
+		if countA == 0 {
+			lineA--
+		}
+		if countB == 0 {
+			lineB--
+		}
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", lineA, countA, lineB, countB)
+		for _, op := range ops[start:end] {
+			fmt.Fprintf(&out, "%c%s\n", op.Kind, op.Line)
+		}
+		i = end
+	}
+
+	return out.String()
+}
+
+// Cells of the Myers trace kept for backtracking, about 32 MB. Larger edit scripts fall back to
+// replacing the whole changed block, which is still a valid diff.
+const diffTraceLimit = 1 << 22
+
+// Myers shortest edit script, the common prefix and suffix are skipped
+func myersDiff(a []string, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, myersEditScript(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// Myers edit script keeping only the diagonals -d-1..d+1 of each step, the ones backtracking reads
+func myersEditScript(a []string, b []string) []diffOp {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+	cells := 0
+
+	for d := 0; d <= max; d++ {
+		cells += 2*d + 3
+		if cells > diffTraceLimit {
+			return replaceDiff(a, b)
+		}
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[offset-d-1:offset+d+2])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackDiff(trace, a, b)
+			}
+		}
+	}
+	return nil
+}
+
+// Edit script removing every line of a and adding every line of b
+func replaceDiff(a []string, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a {
+		ops = append(ops, diffOp{'-', line})
+	}
+	for _, line := range b {
+		ops = append(ops, diffOp{'+', line})
+	}
+	return ops
+}
+
+// Walk the Myers trace backwards to build the edit script
+func backtrackDiff(trace [][]int, a []string, b []string) []diffOp {
+	var ops []diffOp
+	x, y := len(a), len(b)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		// Snapshot of step d holds the diagonals -d-1..d+1
+		v := func(k int) int {
+			return trace[d][k+d+1]
+		}
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v(k-1) < v(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{'+', b[y-1]})
+			} else {
+				ops = append(ops, diffOp{'-', a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	// Reverse
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
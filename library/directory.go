@@ -25,6 +25,16 @@ func InitiateDirectoryFunction(flags Flag) {
 		fmt.Println("📁 Standardize Directory Name:", *flags.Path)
 		StandardizeDirectoryNameLoop(*flags.Path)
 	}
+	/** Compare Directory Trees */
+	if *flags.Dir && *flags.Diff && len(*flags.ComparePaths) > 0 {
+		comparePath := strings.TrimSpace(strings.Split((*flags.ComparePaths)[0], ",")[0])
+		entries, err := DirectoryDiff(*flags.Path, comparePath, *flags.Hash, *flags.Exclude)
+		if err != nil {
+			fmt.Println("❌ Error comparing directories:", err)
+			return
+		}
+		PrintDirectoryDiff(*flags.Path, comparePath, entries, *flags.Format, *flags.Patch)
+	}
 }
 
 // Create a helper function to determine the depth of a directory.
@@ -20,14 +20,59 @@ import (
 // Initiate File Function
 func InitiateFileFunction(flags Flag) {
 	/** Minify Files in Path .js and .css */
-	if *flags.Minify {
+	if *flags.Minify && !*flags.JSON {
 		minifyFiles(*flags.Path)
 	}
 	/** Count Files Containing Text */
-	if *flags.File && *flags.Count && *flags.Text != "" {
+	if *flags.File && *flags.Count && *flags.Text != "" && !*flags.Search {
 		count := CountFilesContainingText(*flags.Path, *flags.Text, *flags.Exclude)
 		fmt.Println("🐙 There are", count, "files containing", *flags.Text)
 	}
+	/** Search File Contents */
+	if *flags.File && *flags.Search {
+		patterns := *flags.Pattern
+		if *flags.Text != "" && *flags.FixedStrings {
+			patterns = append(patterns, *flags.Text)
+		} else if *flags.Text != "" {
+			patterns = append(patterns, regexp.QuoteMeta(*flags.Text))
+		}
+		options := SearchOptions{
+			Patterns:     patterns,
+			FixedStrings: *flags.FixedStrings,
+			IgnoreCase:   *flags.IgnoreCase,
+			Word:         *flags.Word,
+			Context:      *flags.Context,
+		}
+		results, err := SearchFiles(*flags.Path, NewFileFilter(flags), options, *flags.Workers)
+		if err != nil {
+			fmt.Println("❌ Error searching files:", err)
+		} else {
+			PrintSearchResults(results, *flags.Format, *flags.Count, *flags.FilesWithoutMatch, *flags.Context)
+		}
+	}
+	/** Normalize Text Files */
+	if *flags.File && *flags.Normalize {
+		options := NormalizeOptions{
+			EOL:          *flags.EOL,
+			StripBOM:     *flags.StripBOM,
+			TrimTrailing: *flags.TrimTrailing,
+			FinalNewline: *flags.FinalNewline,
+			ToUTF8:       *flags.ToUTF8,
+			Encoding:     *flags.Encoding,
+		}
+		if options.EOL == "" && !options.StripBOM && !options.TrimTrailing && !options.FinalNewline && !options.ToUTF8 {
+			options = NormalizeOptions{EOL: "lf", StripBOM: true, TrimTrailing: true, FinalNewline: true, ToUTF8: true, Encoding: *flags.Encoding}
+		}
+		results, err := NormalizeFiles(*flags.Path, NewFileFilter(flags), options, *flags.Check, *flags.Workers)
+		if err != nil {
+			fmt.Println("❌ Error normalizing files:", err)
+			os.Exit(1)
+		}
+		PrintNormalizeResults(results, *flags.Format, *flags.Check)
+		if *flags.Check && len(results) > 0 {
+			os.Exit(1)
+		}
+	}
 	// Sort Files by date
 	if *flags.File && *flags.Sort && *flags.SortOrder != "" {
 		SortFilesByDate(*flags.Path, *flags.SortOrder)
@@ -99,6 +144,25 @@ func InitiateFileFunction(flags Flag) {
 	if *flags.File && *flags.Remove && len(*flags.Filename) > 0 {
 		DeleteDirectoriesorFilesinPath(*flags.Path, *flags.Dirname, *flags.Filename)
 	}
+	/** Clean Empty Files, Empty Directories and Broken Symlinks */
+	if *flags.File && *flags.Clean {
+		options := CleanOptions{
+			EmptyFiles:  *flags.EmptyFiles,
+			EmptyDirs:   *flags.EmptyDirs,
+			BrokenLinks: *flags.BrokenLinks,
+			DryRun:      *flags.DryRun,
+			Exclude:     *flags.Exclude,
+		}
+		if !options.EmptyFiles && !options.EmptyDirs && !options.BrokenLinks {
+			options.EmptyFiles, options.EmptyDirs, options.BrokenLinks = true, true, true
+		}
+		report, err := CleanPath(*flags.Path, options)
+		if err != nil {
+			fmt.Println("❌ Error cleaning path:", err)
+		} else {
+			PrintCleanReport(report, *flags.Format, *flags.DryRun)
+		}
+	}
 	/** Exctract Links from Directory Path */
 	if *flags.ExtractUrl {
 		urls, err := ExtractURLsFromDirectoryPath(*flags.Path, *flags.Url)
@@ -529,19 +593,6 @@ func DeleteDirectoriesorFilesinPath(root string, dirnames []string, filenames []
 			} else {
 				fmt.Println("✅ Successfully remove files nested by filename", info.Name(), "in", root)
 			}
-		} else if info.IsDir() {
-			// Check if the directory is empty
-			f, err := os.Open(path)
-			if err != nil {
-				fmt.Println("❌ ", err)
-				return nil
-			}
-			defer f.Close()
-			_, err = f.Readdirnames(1)
-			if err == io.EOF {
-				// Directory is empty, so delete it
-				os.Remove(path)
-			}
 		}
 
 		return nil
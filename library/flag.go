@@ -8,201 +8,333 @@ import (
 // Flag Structure
 type Flag struct {
 	// Mode
-	Between            *bool
-	ChatGPT            *bool
-	Contribution       *bool
-	Dir                *bool
-	Docker             *bool
-	DockerCompose      *bool
-	DryRun             *bool
-	Extract            *bool
-	ExtractUrl         *bool
-	File               *bool
-	Find               *bool
-	Git                *bool
-	Gone               *bool
-	Help               *bool
-	Install            *bool
-	ListClass          *bool
-	ListFunction       *bool
-	ListFunctionCall   *bool
-	Minify             *bool
-	Markdown           *bool
-	NoIP               *bool
-	OlderThan          *bool
-	PHP                *bool
-	PHPCS              *bool
-	QuoteofTheDay      *bool
-	Random             *bool
-	Remove             *bool
-	RemoveConflicts    *bool
+	Between               *bool
+	ChatGPT               *bool
+	CheckLinks            *bool
+	Clean                 *bool
+	Contribution          *bool
+	Heatmap               *bool
+	Dir                   *bool
+	Docker                *bool
+	Diff                  *bool
+	DockerCompose         *bool
+	DryRun                *bool
+	Extract               *bool
+	Fmt                   *bool
+	Export                *bool
+	ExtractUrl            *bool
+	File                  *bool
+	Find                  *bool
+	Git                   *bool
+	Graph                 *bool
+	Gone                  *bool
+	Help                  *bool
+	Install               *bool
+	JSON                  *bool
+	ListClass             *bool
+	ListFunction          *bool
+	ListFunctionCall      *bool
+	Links                 *bool
+	Lint                  *bool
+	Minify                *bool
+	Normalize             *bool
+	Mirror                *bool
+	Markdown              *bool
+	NoIP                  *bool
+	OlderThan             *bool
+	PHP                   *bool
+	PHPCS                 *bool
+	QuoteofTheDay         *bool
+	Random                *bool
+	Remove                *bool
+	RemoveConflicts       *bool
 	RemoveDuplicatedFiles *bool
-	RemoveLink         *bool
-	RemoveFunction     *bool
-	ResetCache         *bool
-	Rsync              *bool
-	Stats              *bool
-	Sort               *bool
-	SearchTemplate     *bool
-	SearchandReplace   *bool
-	Standardize        *bool
-	Subdirectory       *bool
-	Syncthing          *bool
-	WPClean            *bool
-	WPPluginBuild      *bool
-	WPPluginBuildCheck *bool
-	WPPluginRelease    *bool
-	WPThemeBuild       *bool
-	WPThemeBuildCheck  *bool
-	WPTagTrunk         *bool
-	WPRefactor         *bool
-	SelfUpdate         *bool
-	Tree               *bool
-	Update             *bool
-	XML                *bool
-	YoungerThan        *bool
-	YouTube            *bool
+	RemoveLink            *bool
+	RemoveFunction        *bool
+	ResetCache            *bool
+	Rsync                 *bool
+	Stats                 *bool
+	Sort                  *bool
+	Toc                   *bool
+	Tasks                 *bool
+	SearchTemplate        *bool
+	Search                *bool
+	SearchandReplace      *bool
+	Standardize           *bool
+	Subdirectory          *bool
+	Syncthing             *bool
+	WPClean               *bool
+	WPPluginBuild         *bool
+	WPPluginBuildCheck    *bool
+	WPPluginRelease       *bool
+	WPThemeBuild          *bool
+	WPThemeBuildCheck     *bool
+	WPTagTrunk            *bool
+	WPRefactor            *bool
+	SelfUpdate            *bool
+	Sitemap               *bool
+	Tree                  *bool
+	Update                *bool
+	XML                   *bool
+	YoungerThan           *bool
+	YouTube               *bool
 
 	// Bool Parameters
-	Count      *bool
-	Production *bool
-	Prune      *bool
-	Reset      *bool
-	Restart    *bool
-	Version    *bool
+	Checksum          *bool
+	Check             *bool
+	BrokenLinks       *bool
+	Count             *bool
+	Delete            *bool
+	DirsFirst         *bool
+	DeadEnds          *bool
+	EmptyDirs         *bool
+	EmptyFiles        *bool
+	FilesWithoutMatch *bool
+	FinalNewline      *bool
+	Generate          *bool
+	FixedStrings      *bool
+	Hash              *bool
+	IgnoreCase        *bool
+	Patch             *bool
+	Pretty            *bool
+	Merge             *bool
+	Orphans           *bool
+	Production        *bool
+	Prune             *bool
+	Relative          *bool
+	Reset             *bool
+	StripBOM          *bool
+	Suggest           *bool
+	Validate          *bool
+	ToUTF8            *bool
+	TrimTrailing      *bool
+	Restart           *bool
+	Version           *bool
+	Word              *bool
 
 	// String Parameters
 	API_KEY      *string
+	Bucket       *string
+	Thresholds   *string
+	WeekStart    *string
 	ID           *string
 	Days         *int
 	DateEnd      *string
 	DateStart    *string
 	Dirname      *[]string
 	ComparePaths *[]string
+	Context      *int
+	Config       *string
 	End          *string
+	EOL          *string
+	Encoding     *string
 	Ext          *[]string
 	Except       *[]string
 	Exclude      *[]string
 	Filename     *[]string
+	Format       *string
 	FunctionName *[]string
 	From         *string
 	Heading      *string
+	Input        *string
 	Hostname     *string
 	Keyword      *string
 	Level        *int
 	Limit        *int
+	MaxDepth     *int
 	Message      *string
+	MinDepth     *int
 	Number       *int
+	Output       *string
 	Path         *string
+	Pattern      *[]string
+	Query        *string
+	Backlinks    *string
 	Password     *string
 	Regex        *string
+	Repo         *[]string
+	Field        *[]string
+	Reference    *string
 	Start        *string
+	Status       *string
 	SortOrder    *string
+	Style        *string
+	Tag          *[]string
+	Section      *string
+	Schema       *string
+	Template     *string
 	Text         *string
 	To           *string
 	Type         *string
 	Url          *string
 	Username     *string
+	Workers      *int
 }
 
 // Get Flag
 func GetFlag() Flag {
 	flags := Flag{
 		// Mode
-		Between:            flag.Bool("between", false, "Between Mode"),
-		ChatGPT:            flag.Bool("chatgpt", false, "Chat with GPT-3"),
-		Dir:                flag.Bool("dir", false, "Directory Mode"),
-		Contribution:       flag.Bool("contribution", false, "Contribution Mode"),
-		Docker:             flag.Bool("docker", false, "Docker Mode"),
-		DockerCompose:      flag.Bool("docker-compose", false, "Docker Compose Mode"),
-		DryRun:             flag.Bool("dry-run", false, "Dry Run Mode"),
-		Extract:            flag.Bool("extract", false, "Extract Mode"),
-		ExtractUrl:         flag.Bool("extract-url", false, "Extract URL Mode"),
-		File:               flag.Bool("file", false, "File Mode"),
-		Find:               flag.Bool("find", false, "Find Mode"),
-		Git:                flag.Bool("git", false, "Git Mode"),
-		Gone:               flag.Bool("gone", false, "Gone Mode"),
-		Help:               flag.Bool("help", false, "Help Mode"),
-		Install:            flag.Bool("install", false, "Install Mode"),
-		ListClass:          flag.Bool("list-class", false, "List Class"),
-		ListFunction:       flag.Bool("list-function", false, "List Function"),
-		ListFunctionCall:   flag.Bool("list-function-call", false, "List Function Call"),
-		Markdown:           flag.Bool("md", false, "Markdown Mode"),
-		Minify:             flag.Bool("minify", false, "Minify Mode"),
-		NoIP:               flag.Bool("noip", false, "No-IP Mode"),
-		OlderThan:          flag.Bool("older-than", false, "Older Than Mode"),
-		PHP:                flag.Bool("php", false, "PHP Mode"),
-		PHPCS:              flag.Bool("phpcs", false, "PHP Code Sniffer Mode"),
-		Random:             flag.Bool("random", false, "Random Mode"),
-		Remove:             flag.Bool("remove", false, "Remove Mode for Dir and File"),
-		RemoveConflicts:    flag.Bool("remove-conflicts", false, "Remove Conflicts"),
+		Between:               flag.Bool("between", false, "Between Mode"),
+		ChatGPT:               flag.Bool("chatgpt", false, "Chat with GPT-3"),
+		CheckLinks:            flag.Bool("check-links", false, "Check Links Mode (Markdown)"),
+		Clean:                 flag.Bool("clean", false, "Clean Mode"),
+		Dir:                   flag.Bool("dir", false, "Directory Mode"),
+		Contribution:          flag.Bool("contribution", false, "Contribution Mode"),
+		Heatmap:               flag.Bool("heatmap", false, "Contribution heatmap (Contribution Mode)"),
+		Docker:                flag.Bool("docker", false, "Docker Mode"),
+		Diff:                  flag.Bool("diff", false, "Diff Mode"),
+		DockerCompose:         flag.Bool("docker-compose", false, "Docker Compose Mode"),
+		DryRun:                flag.Bool("dry-run", false, "Dry Run Mode"),
+		Extract:               flag.Bool("extract", false, "Extract Mode"),
+		Fmt:                   flag.Bool("fmt", false, "Format Mode (Markdown)"),
+		Export:                flag.Bool("export", false, "Export Mode (Markdown): Static HTML site"),
+		ExtractUrl:            flag.Bool("extract-url", false, "Extract URL Mode"),
+		File:                  flag.Bool("file", false, "File Mode"),
+		Find:                  flag.Bool("find", false, "Find Mode"),
+		Git:                   flag.Bool("git", false, "Git Mode"),
+		Graph:                 flag.Bool("graph", false, "Graph Mode (Markdown): Note link graph"),
+		Gone:                  flag.Bool("gone", false, "Gone Mode"),
+		Help:                  flag.Bool("help", false, "Help Mode"),
+		Install:               flag.Bool("install", false, "Install Mode"),
+		JSON:                  flag.Bool("json", false, "JSON Mode"),
+		ListClass:             flag.Bool("list-class", false, "List Class"),
+		ListFunction:          flag.Bool("list-function", false, "List Function"),
+		ListFunctionCall:      flag.Bool("list-function-call", false, "List Function Call"),
+		Links:                 flag.Bool("links", false, "Links Mode (Markdown): list, strip or rewrite links"),
+		Lint:                  flag.Bool("lint", false, "Lint Mode (Markdown)"),
+		Markdown:              flag.Bool("md", false, "Markdown Mode"),
+		Minify:                flag.Bool("minify", false, "Minify Mode"),
+		Normalize:             flag.Bool("normalize", false, "Normalize Mode"),
+		Mirror:                flag.Bool("mirror", false, "Native local mirror (Rsync Mode)"),
+		NoIP:                  flag.Bool("noip", false, "No-IP Mode"),
+		OlderThan:             flag.Bool("older-than", false, "Older Than Mode"),
+		PHP:                   flag.Bool("php", false, "PHP Mode"),
+		PHPCS:                 flag.Bool("phpcs", false, "PHP Code Sniffer Mode"),
+		Random:                flag.Bool("random", false, "Random Mode"),
+		Remove:                flag.Bool("remove", false, "Remove Mode for Dir and File"),
+		RemoveConflicts:       flag.Bool("remove-conflicts", false, "Remove Conflicts"),
 		RemoveDuplicatedFiles: flag.Bool("remove-duplicated-files", false, "Remove duplicated files (all file types)"),
-		RemoveLink:         flag.Bool("remove-link", false, "Remove Link from File"),
-		RemoveFunction:     flag.Bool("remove-function", false, "Remove Link from File"),
-		ResetCache:         flag.Bool("reset-cache", false, "Git Reset Cache"),
-		Rsync:              flag.Bool("rsync", false, "Rsync Mode"),
-		Syncthing:          flag.Bool("syncthing", false, "Syncthing Mode"),
-		QuoteofTheDay:      flag.Bool("quote-of-the-day", false, "show quote of the day"),
-		Reset:              flag.Bool("reset", false, "Reset Mode"),
-		Restart:            flag.Bool("restart", false, "Restart (Docker Mode): Container"),
-		SearchTemplate:     flag.Bool("search-template", false, "Search Template"),
-		SearchandReplace:   flag.Bool("search-replace", false, "Search and Replace"),
-		SelfUpdate:         flag.Bool("self-update", false, "self update"),
-		Standardize:        flag.Bool("standardize", false, "Standardize"),
-		Stats:              flag.Bool("stats", false, "show stats"),
-		Subdirectory:       flag.Bool("subdirectory", false, "Subdirectory Mode"),
-		Sort:               flag.Bool("sort", false, "Sort Files by Date"),
-		Tree:               flag.Bool("tree", false, "Tree Mode"),
-		Update:             flag.Bool("update", false, "update"),
-		WPClean:            flag.Bool("wp-clean", false, "WP Clean Project Files for Production"),
-		WPPluginBuild:      flag.Bool("wp-plugin-build", false, "WP Build Plugin Comply"),
-		WPPluginBuildCheck: flag.Bool("wp-plugin-build-check", false, "WP Check Plugin Comply with WordPress.org (Version Check)"),
-		WPPluginRelease:    flag.Bool("wp-plugin-release", false, "WP Build Plugin Release"),
-		WPThemeBuild:       flag.Bool("wp-theme-build", false, "WP Theme Plugin Comply"),
-		WPThemeBuildCheck:  flag.Bool("wp-theme-build-check", false, "WP Check Theme Comply with WordPress.org (Version Check)"),
-		WPTagTrunk:         flag.Bool("wp-tag-trunk", false, "WP Tag Trunk"),
-		WPRefactor:         flag.Bool("wp-refactor", false, "Refactor Library"),
-		XML:                flag.Bool("xml", false, "XML Mode"),
-		YoungerThan:        flag.Bool("younger-than", false, "Younger Than Mode"),
-		YouTube:            flag.Bool("youtube", false, "YouTube Mode"),
+		RemoveLink:            flag.Bool("remove-link", false, "Remove Link from File"),
+		RemoveFunction:        flag.Bool("remove-function", false, "Remove Link from File"),
+		ResetCache:            flag.Bool("reset-cache", false, "Git Reset Cache"),
+		Rsync:                 flag.Bool("rsync", false, "Rsync Mode"),
+		Syncthing:             flag.Bool("syncthing", false, "Syncthing Mode"),
+		QuoteofTheDay:         flag.Bool("quote-of-the-day", false, "show quote of the day"),
+		Reset:                 flag.Bool("reset", false, "Reset Mode"),
+		StripBOM:              flag.Bool("strip-bom", false, "Strip UTF-8 BOM (Normalize Mode)"),
+		Suggest:               flag.Bool("suggest", false, "Suggest closest existing target (Markdown Check Links)"),
+		Validate:              flag.Bool("validate", false, "Validate files (JSON Mode)"),
+		ToUTF8:                flag.Bool("to-utf8", false, "Convert legacy encodings to UTF-8 (Normalize Mode)"),
+		TrimTrailing:          flag.Bool("trim-trailing", false, "Trim trailing whitespace (Normalize Mode)"),
+		Restart:               flag.Bool("restart", false, "Restart (Docker Mode): Container"),
+		SearchTemplate:        flag.Bool("search-template", false, "Search Template"),
+		Search:                flag.Bool("search", false, "Search Mode"),
+		SearchandReplace:      flag.Bool("search-replace", false, "Search and Replace"),
+		SelfUpdate:            flag.Bool("self-update", false, "self update"),
+		Sitemap:               flag.Bool("sitemap", false, "Sitemap Mode"),
+		Standardize:           flag.Bool("standardize", false, "Standardize"),
+		Stats:                 flag.Bool("stats", false, "show stats"),
+		Subdirectory:          flag.Bool("subdirectory", false, "Subdirectory Mode"),
+		Sort:                  flag.Bool("sort", false, "Sort Files by Date"),
+		Toc:                   flag.Bool("toc", false, "Table of Contents Mode (Markdown)"),
+		Tasks:                 flag.Bool("tasks", false, "Tasks Mode (Markdown): Checkbox aggregation"),
+		Tree:                  flag.Bool("tree", false, "Tree Mode"),
+		Update:                flag.Bool("update", false, "update"),
+		WPClean:               flag.Bool("wp-clean", false, "WP Clean Project Files for Production"),
+		WPPluginBuild:         flag.Bool("wp-plugin-build", false, "WP Build Plugin Comply"),
+		WPPluginBuildCheck:    flag.Bool("wp-plugin-build-check", false, "WP Check Plugin Comply with WordPress.org (Version Check)"),
+		WPPluginRelease:       flag.Bool("wp-plugin-release", false, "WP Build Plugin Release"),
+		WPThemeBuild:          flag.Bool("wp-theme-build", false, "WP Theme Plugin Comply"),
+		WPThemeBuildCheck:     flag.Bool("wp-theme-build-check", false, "WP Check Theme Comply with WordPress.org (Version Check)"),
+		WPTagTrunk:            flag.Bool("wp-tag-trunk", false, "WP Tag Trunk"),
+		WPRefactor:            flag.Bool("wp-refactor", false, "Refactor Library"),
+		XML:                   flag.Bool("xml", false, "XML Mode"),
+		YoungerThan:           flag.Bool("younger-than", false, "Younger Than Mode"),
+		YouTube:               flag.Bool("youtube", false, "YouTube Mode"),
 
 		// Bool Parameters
-		Count:      flag.Bool("count", false, "Count Mode"),
-		Production: flag.Bool("production", false, "Production (WP Mode): Production Environment"),
-		Prune:      flag.Bool("prune", false, "Prune (Docker Mode): Container"),
-		Version:    flag.Bool("version", false, "show current version"),
+		Checksum:          flag.Bool("checksum", false, "Compare files by checksum instead of mtime and size (Mirror Mode)"),
+		Check:             flag.Bool("check", false, "Check only, exit non-zero when changes are needed"),
+		BrokenLinks:       flag.Bool("broken-links", false, "Broken symlinks (Clean Mode)"),
+		Count:             flag.Bool("count", false, "Count Mode"),
+		Delete:            flag.Bool("delete", false, "Delete extraneous files from destination (Mirror Mode)"),
+		DirsFirst:         flag.Bool("dirs-first", false, "List directories before files (Markdown Tree)"),
+		DeadEnds:          flag.Bool("dead-ends", false, "Notes without outgoing links (Markdown Graph)"),
+		EmptyDirs:         flag.Bool("empty-dirs", false, "Empty directories (Clean Mode)"),
+		EmptyFiles:        flag.Bool("empty-files", false, "Zero-byte files (Clean Mode)"),
+		FilesWithoutMatch: flag.Bool("files-without-match", false, "List files without match (Search Mode)"),
+		FinalNewline:      flag.Bool("final-newline", false, "Enforce a final newline (Normalize Mode)"),
+		Generate:          flag.Bool("generate", false, "Generate (Sitemap Mode)"),
+		FixedStrings:      flag.Bool("fixed-strings", false, "Treat patterns as literal text (Search Mode)"),
+		Hash:              flag.Bool("hash", false, "Compare file content by hash (Diff Mode)"),
+		IgnoreCase:        flag.BoolP("ignore-case", "i", false, "Case insensitive matching (Search Mode)"),
+		Patch:             flag.Bool("patch", false, "Show unified diff for changed text files (Diff Mode)"),
+		Pretty:            flag.Bool("pretty", false, "Pretty print with sorted keys (JSON Mode)"),
+		Merge:             flag.Bool("merge", false, "Merge files, later files override (JSON Mode)"),
+		Orphans:           flag.Bool("orphans", false, "Notes without backlinks (Markdown Graph)"),
+		Production:        flag.Bool("production", false, "Production (WP Mode): Production Environment"),
+		Prune:             flag.Bool("prune", false, "Prune (Docker Mode): Container"),
+		Relative:          flag.Bool("relative", false, "Convert absolute links under --url to relative paths (Markdown Links)"),
+		Version:           flag.Bool("version", false, "show current version"),
+		Word:              flag.Bool("word", false, "Match whole words only (Search Mode)"),
 
 		// String Parameters
 		API_KEY:      flag.String("api-key", "", "API Key"),
+		Bucket:       flag.String("bucket", "", "Timeline bucket (Contribution Mode): day|week|month"),
+		Thresholds:   flag.String("thresholds", "", "Heatmap level thresholds, e.g. 1,3,6,10 (Contribution Mode)"),
+		WeekStart:    flag.String("week-start", "", "Heatmap week start (Contribution Mode): sunday|monday"),
 		ID:           flag.String("id", "", "Identifier (Docker Mode): Container"),
 		DateEnd:      flag.String("date-end", "", "Date End"),
 		DateStart:    flag.String("date-start", "", "Date Start"),
 		Days:         flag.Int("days", 0, "Days (Older Than Mode): Days"),
 		Dirname:      flag.StringArray("dirname", []string{}, "Directory Name (Dir Mode): Directory Name"),
 		ComparePaths: flag.StringArray("compare-paths", []string{}, "Comma-separated paths to compare for duplicate files (all file types)"),
+		Context:      flag.IntP("context", "C", 0, "Number of context lines (Search Mode)"),
+		Config:       flag.String("config", "", "Config file (Markdown Lint): default markdown.json"),
 		End:          flag.String("end", "", "End Date"),
+		EOL:          flag.String("eol", "", "Line endings (Normalize Mode): lf|crlf"),
+		Encoding:     flag.String("encoding", "windows-1252", "Legacy encoding of non UTF-8 files (Normalize Mode): windows-1252|iso-8859-1|iso-8859-15"),
 		Ext:          flag.StringArray("ext", []string{}, "File extensions to include"),
 		Except:       flag.StringArray("except", []string{}, "File to exclude"),
 		Exclude:      flag.StringArray("exclude", []string{}, "Path to exclude"),
 		Filename:     flag.StringArrayP("filename", "f", []string{}, "Filenames"),
+		Format:       flag.String("format", "", "Output format (table|json)"),
 		FunctionName: flag.StringArray("functionname", []string{}, "Function Name"),
 		From:         flag.String("from", "", "Refactor Text From"),
 		Heading:      flag.String("heading", "", "Heading"),
+		Input:        flag.String("input", "", "Input file, \"-\" or empty reads stdin (Markdown Section)"),
 		Hostname:     flag.String("hostname", "", "Hostname"),
 		Keyword:      flag.String("keyword", "", "Keyword"),
 		Level:        flag.Int("level", 0, "Directory Level (Dir Mode): Directory Level"),
 		Limit:        flag.Int("limit", 0, "Number of limit"),
+		MaxDepth:     flag.Int("max-depth", 6, "Maximum heading level (Markdown TOC)"),
 		Message:      flag.StringP("message", "m", "", "Message (Git Mode): Commit Message"),
+		MinDepth:     flag.Int("min-depth", 1, "Minimum heading level (Markdown TOC)"),
 		Number:       flag.IntP("number", "n", 0, "Number of random files"),
+		Output:       flag.StringP("output", "o", "", "Output path"),
 		Path:         flag.String("path", "", "Refactor : Path to Directory"),
+		Pattern:      flag.StringArrayP("pattern", "e", []string{}, "Search pattern, regex unless --fixed-strings (Search Mode)"),
+		Query:        flag.String("query", "", "Front matter query, e.g. \"status=draft tag=wordpress sort=-date\" (Markdown Query)"),
+		Backlinks:    flag.String("backlinks", "", "List backlinks of a note (Markdown Graph)"),
 		Password:     flag.StringP("password", "p", "", "Password"),
 		Regex:        flag.String("regex", "", "Regex"),
+		Repo:         flag.StringArray("repo", []string{}, "Git repository, repeatable (Contribution Mode)"),
+		Field:        flag.StringArray("field", []string{}, "Field path, repeatable or comma separated (Search Template)"),
+		Reference:    flag.String("reference", "", "Reference date of relative dates, defaults to the file modification time (YouTube Mode)"),
 		Start:        flag.String("start", "", "Start Date"),
+		Status:       flag.String("status", "", "Task status (Markdown Tasks): open|done|cancelled|all"),
 		SortOrder:    flag.String("sort-order", "", "Sort Order"),
+		Style:        flag.String("style", "", "Tree style (Markdown Tree): markdown|ascii|unicode"),
+		Tag:          flag.StringArray("tag", []string{}, "Tag filter (Markdown Tasks)"),
+		Section:      flag.String("section", "", "Section operation with --heading (Markdown Section): replace|append|prepend|insert"),
+		Schema:       flag.String("schema", "", "Schema name or file (JSON Mode): rsync|phpcs-config|phpcs-standards|wordpress-config"),
+		Template:     flag.String("template", "", "Template file (Markdown Export)"),
 		Text:         flag.String("text", "", "Text"),
 		To:           flag.String("to", "", "Refactor Text To"),
 		Type:         flag.String("type", "", "Build type (WordPress)"),
 		Username:     flag.StringP("username", "u", "", "Username"),
+		Workers:      flag.Int("workers", 0, "Number of parallel workers (default: number of CPUs)"),
 		Url:          flag.String("url", "", "Url"),
 	}
 	flag.Parse()
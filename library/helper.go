@@ -1,13 +1,17 @@
 package library
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"text/tabwriter"
 )
 
 // Read File
@@ -64,6 +68,13 @@ func SliceContainsString(elems []string, v string) bool {
 	return false
 }
 
+// Check if two paths point to the same location, compared as absolute cleaned paths
+func IsSamePath(a string, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
 // Get Shell Remove Function
 func GetShellRemoveFunction(path string) string {
 	if strings.Contains(path, "*") {
@@ -88,3 +99,63 @@ func getDataFromAPI(url string) []byte {
 	}
 	return responseData
 }
+
+// Print rows as an aligned table
+func PrintTable(headers []string, rows [][]string) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+	writer.Flush()
+}
+
+// Print value as indented JSON
+func PrintJSON(v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		fmt.Println("❌ Error encoding JSON:", err)
+	}
+}
+
+// Check if a file looks binary (contains a NUL byte in the first 8000 bytes)
+func IsBinaryFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	buffer := make([]byte, 8000)
+	n, _ := file.Read(buffer)
+	return bytes.IndexByte(buffer[:n], 0) != -1
+}
+
+// Levenshtein distance between two strings
+func LevenshteinDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j] + 1
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+			if previous[j-1]+cost < current[j] {
+				current[j] = previous[j-1] + cost
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
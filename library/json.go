@@ -3,77 +3,300 @@ package library
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 )
 
-// TemplateData struct to hold the structure of your JSON data.
-type TemplateData struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+// Template Match of a search, with the best matching value of each field
+type TemplateMatch struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	File    string            `json:"file"`
+	Score   float64           `json:"score"`
+	Matched map[string]string `json:"matched"`
 }
 
+// Default template search fields
+var templateSearchFields = []string{"name", "description", "tags"}
+
+// Minimum fuzzy score of the letters in order match
+const fuzzyMinimumScore = 0.15
+
 // Initiate Json Function
 func InitiateJsonFunction(flags Flag) {
 	// Find Matching Name and Description.
 	if *flags.SearchTemplate && *flags.Keyword != "" {
-		matchingNames, err := findMatchingTemplates(*flags.Path, *flags.Keyword)
+		var fields []string
+		for _, field := range *flags.Field {
+			fields = append(fields, strings.Split(field, ",")...)
+		}
+		matches, warnings, err := SearchTemplates(*flags.Path, *flags.Exclude, strings.Fields(*flags.Keyword), fields)
 		if err != nil {
 			fmt.Printf("❌ error finding matching templates: %v\n", err)
 			return
 		}
-		for _, name := range matchingNames {
-			fmt.Println(name)
+		for _, warning := range warnings {
+			fmt.Fprintln(os.Stderr, "⚠️", warning)
 		}
+		if *flags.Limit > 0 && len(matches) > *flags.Limit {
+			matches = matches[:*flags.Limit]
+		}
+		PrintTemplateMatches(matches, *flags.Format)
+	}
+	// Pretty print, minify, validate, query, merge and diff JSON files
+	if *flags.JSON {
+		jsonToolbox(flags)
 	}
 }
 
-// Find Matching Template
-func findMatchingTemplates(dirPath string, keyword string) ([]string, error) {
-	var matchingNames []string
+// Search the JSON templates of a directory recursively. Every keyword must match one of the fields.
+// Fields are dot paths such as "meta.author" or "tags", arrays are searched element by element and "*" matches any key.
+// Results are ranked by score, files that can't be read or parsed are returned as warnings.
+func SearchTemplates(dirPath string, exclude []string, keywords []string, fields []string) ([]TemplateMatch, []string, error) {
+	if len(fields) == 0 {
+		fields = templateSearchFields
+	}
+	root := dirPath
+	if root == "" {
+		root, _ = os.Getwd()
+	}
 
-	// Read all files in the directory.
-	files, err := ioutil.ReadDir(dirPath)
+	files, err := FileFilter{Exclude: exclude, Ext: []string{".json"}}.CollectFiles(root)
 	if err != nil {
-		return nil, fmt.Errorf("error reading directory: %w", err)
+		return nil, nil, fmt.Errorf("error reading directory: %w", err)
 	}
 
-	// Iterate over the files.
+	var matches []TemplateMatch
+	var warnings []string
 	for _, file := range files {
-		// Construct the full file path.
-		filePath := filepath.Join(dirPath, file.Name())
-
-		// Check if it's a file and has a .json extension.
-		if file.Mode().IsRegular() && strings.ToLower(filepath.Ext(file.Name())) == ".json" {
-			// Read the file content.
-			fileContent, err := ioutil.ReadFile(filePath)
-			if err != nil {
-				fmt.Printf("error reading file %s: %v\n", filePath, err)
-				// IMPORTANT:  Handle the error.  Here, we print an error and *continue*
-				// to the next file.  You might want to return an error, depending
-				// on your needs.  Returning here would stop processing *all* files.
-				continue // Skip to the next file.
+		content, err := os.ReadFile(file)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("error reading file %s: %v", file, err))
+			continue
+		}
+		var data interface{}
+		if err := json.Unmarshal(content, &data); err != nil {
+			warnings = append(warnings, fmt.Sprintf("error unmarshalling JSON from %s: %v", file, err))
+			continue
+		}
+
+		// Best value of any field for each keyword, a template matches when every keyword does
+		match := TemplateMatch{Matched: make(map[string]string)}
+		for _, keyword := range keywords {
+			best, bestField, bestValue := 0.0, "", ""
+			for _, field := range fields {
+				for _, value := range JSONFieldValues(data, field) {
+					if score := FuzzyScore(keyword, value); score > best {
+						best, bestField, bestValue = score, field, value
+					}
+				}
+			}
+			if best == 0 {
+				match.Score = 0
+				break
+			}
+			match.Score += best / float64(len(keywords))
+			if matched, ok := match.Matched[bestField]; ok && matched != bestValue {
+				bestValue = matched + ", " + bestValue
 			}
+			match.Matched[bestField] = bestValue
+		}
+		if match.Score == 0 {
+			continue
+		}
+
+		rel, _ := filepath.Rel(root, file)
+		match.File = filepath.ToSlash(rel)
+		match.ID = jsonFieldString(data, "id")
+		match.Name = jsonFieldString(data, "name")
+		matches = append(matches, match)
+	}
 
-			// Unmarshal the JSON data into the TemplateData struct.
-			var data TemplateData
-			err = json.Unmarshal(fileContent, &data)
-			if err != nil {
-				fmt.Printf("error unmarshalling JSON from %s: %v\n", filePath, err)
-				continue // Skip to the next file.  Handle the error as appropriate.
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].File < matches[j].File
+	})
+	return matches, warnings, nil
+}
+
+// String values of a dot path in decoded JSON, arrays are flattened and numbers and booleans are formatted
+func JSONFieldValues(data interface{}, path string) []string {
+	nodes := []interface{}{data}
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			var next []interface{}
+			for _, node := range nodes {
+				next = append(next, jsonChildren(node, key)...)
 			}
+			nodes = next
+		}
+	}
 
-			// Check if the keyword is found in either the "name" or "description" (case-insensitive).
-			keywordLower := strings.ToLower(keyword)
-			nameLower := strings.ToLower(data.Name)
-			descriptionLower := strings.ToLower(data.Description)
+	var values []string
+	var collect func(node interface{})
+	collect = func(node interface{}) {
+		switch value := node.(type) {
+		case string:
+			values = append(values, value)
+		case float64:
+			values = append(values, strconv.FormatFloat(value, 'f', -1, 64))
+		case bool:
+			values = append(values, strconv.FormatBool(value))
+		case []interface{}:
+			for _, item := range value {
+				collect(item)
+			}
+		}
+	}
+	for _, node := range nodes {
+		collect(node)
+	}
+	return values
+}
 
-			if strings.Contains(nameLower, keywordLower) || strings.Contains(descriptionLower, keywordLower) {
-				matchingNames = append(matchingNames, data.Name)
+// Children of a node for a key, arrays are searched element by element and "*" matches any key
+func jsonChildren(node interface{}, key string) []interface{} {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if key == "*" {
+			keys := make([]string, 0, len(value))
+			for k := range value {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			var children []interface{}
+			for _, k := range keys {
+				children = append(children, value[k])
+			}
+			return children
+		}
+		if child, ok := value[key]; ok {
+			return []interface{}{child}
+		}
+	case []interface{}:
+		if index, err := strconv.Atoi(key); err == nil {
+			if index >= 0 && index < len(value) {
+				return []interface{}{value[index]}
 			}
+			return nil
 		}
+		var children []interface{}
+		for _, item := range value {
+			children = append(children, jsonChildren(item, key)...)
+		}
+		return children
 	}
-	return matchingNames, nil
-}
\ No newline at end of file
+	return nil
+}
+
+// First value of a field as string
+func jsonFieldString(data interface{}, path string) string {
+	if values := JSONFieldValues(data, path); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Fuzzy score of a keyword in a text between 0 and 1: exact match, substring at a word start, substring,
+// a word within typo distance and finally the letters of the keyword in order within a word
+func FuzzyScore(keyword string, text string) float64 {
+	keyword, text = strings.ToLower(strings.TrimSpace(keyword)), strings.ToLower(text)
+	if keyword == "" || text == "" {
+		return 0
+	}
+	if keyword == text {
+		return 1
+	}
+
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r > 127)
+	})
+	if index := strings.Index(text, keyword); index >= 0 {
+		for _, word := range words {
+			if word == keyword {
+				return 0.9
+			}
+		}
+		if index == 0 || strings.Contains(" -_./", string(text[index-1])) {
+			return 0.8
+		}
+		return 0.6
+	}
+
+	// Typos, one edit per four letters
+	best := 0.0
+	length := len([]rune(keyword))
+	for _, word := range words {
+		distance := LevenshteinDistance(keyword, word)
+		if distance <= length/4 {
+			longest := length
+			if len([]rune(word)) > longest {
+				longest = len([]rune(word))
+			}
+			if score := 0.5 * (1 - float64(distance)/float64(longest)); score > best {
+				best = score
+			}
+		}
+	}
+	if best > 0 {
+		return best
+	}
+
+	// Letters in order within a single word, scored by how close together they are. Spans over
+	// twice the keyword length score below the minimum and don't count, e.g. "api" in "anticipation".
+	runes := []rune(keyword)
+	for _, word := range words {
+		letters := []rune(word)
+		for start := range letters {
+			if letters[start] != runes[0] {
+				continue
+			}
+			position := 0
+			for i := start; i < len(letters) && position < len(runes); i++ {
+				if letters[i] == runes[position] {
+					position++
+					if position == len(runes) {
+						if score := 0.3 * float64(len(runes)) / float64(i-start+1); score > best {
+							best = score
+						}
+					}
+				}
+			}
+		}
+	}
+	if best < fuzzyMinimumScore {
+		return 0
+	}
+	return best
+}
+
+// Print template matches as table or json
+func PrintTemplateMatches(matches []TemplateMatch, format string) {
+	if format == "json" {
+		if matches == nil {
+			matches = []TemplateMatch{}
+		}
+		PrintJSON(matches)
+		return
+	}
+
+	var rows [][]string
+	for _, match := range matches {
+		fields := make([]string, 0, len(match.Matched))
+		for field, value := range match.Matched {
+			if len([]rune(value)) > 60 {
+				value = string([]rune(value)[:57]) + "..."
+			}
+			fields = append(fields, field+"="+value)
+		}
+		sort.Strings(fields)
+		rows = append(rows, []string{strconv.FormatFloat(match.Score, 'f', 2, 64), match.ID, match.Name, match.File, strings.Join(fields, "; ")})
+	}
+	PrintTable([]string{"SCORE", "ID", "NAME", "FILE", "MATCHED"}, rows)
+	fmt.Println("🐙 There are", len(matches), "matching templates")
+}
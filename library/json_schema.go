@@ -0,0 +1,551 @@
+package library
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSON Schema Error of an instance path
+type JSONSchemaError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e JSONSchemaError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// JSON Schema validator for a draft 2020-12 subset: type, enum, const, properties, required,
+// additionalProperties, patternProperties, propertyNames, min/maxProperties, items, prefixItems,
+// min/maxItems, uniqueItems, contains, min/maxLength, pattern, minimum, maximum, exclusiveMinimum,
+// exclusiveMaximum, multipleOf, allOf, anyOf, oneOf, not, if/then/else and local $ref to $defs
+type JSONSchema struct {
+	root     interface{}
+	patterns map[string]*regexp.Regexp
+}
+
+// Schemas of the aspri config files
+var jsonSchemas = map[string]string{
+	"rsync": `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "rsync.json",
+  "type": "object",
+  "properties": {
+    "flags": { "type": "string" },
+    "source": { "$ref": "#/$defs/directory" },
+    "destination": { "$ref": "#/$defs/directory" },
+    "excludes": { "type": "array", "items": { "type": "string", "minLength": 1 } }
+  },
+  "required": ["source", "destination"],
+  "additionalProperties": false,
+  "$defs": {
+    "directory": {
+      "type": "object",
+      "properties": {
+        "remote": { "type": "string" },
+        "path": { "type": "string", "minLength": 1 }
+      },
+      "required": ["path"],
+      "additionalProperties": false
+    }
+  }
+}`,
+	"phpcs-config": `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "PHPCS config.json",
+  "type": "object",
+  "properties": {
+    "phpcs": { "type": "string", "minLength": 1 }
+  },
+  "required": ["phpcs"],
+  "additionalProperties": false
+}`,
+	"phpcs-standards": `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "PHPCS standards.json",
+  "type": "array",
+  "items": { "type": "string", "minLength": 1 },
+  "uniqueItems": true
+}`,
+	"wordpress-config": `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "WordPress plugin or theme config.json",
+  "type": "object",
+  "properties": {
+    "production": { "type": "boolean" },
+    "version": { "type": "string", "pattern": "^\\d+(\\.\\d+)*([-+.][0-9A-Za-z.-]+)?$" }
+  }
+}`,
+}
+
+// Names of the embedded schemas
+func JSONSchemaNames() []string {
+	var names []string
+	for name := range jsonSchemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load an embedded schema by name or a schema file
+func LoadJSONSchema(nameOrPath string) (*JSONSchema, error) {
+	content, ok := jsonSchemas[nameOrPath]
+	if !ok {
+		data, err := os.ReadFile(nameOrPath)
+		if err != nil {
+			return nil, fmt.Errorf("unknown schema %s (%s or a schema file): %v", nameOrPath, strings.Join(JSONSchemaNames(), "|"), err)
+		}
+		content = string(data)
+	}
+	return NewJSONSchema([]byte(content))
+}
+
+// Parse a schema
+func NewJSONSchema(content []byte) (*JSONSchema, error) {
+	root, err := DecodeJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %v", err)
+	}
+	return &JSONSchema{root: root, patterns: make(map[string]*regexp.Regexp)}, nil
+}
+
+// Compiled pattern, cached by source
+func (s *JSONSchema) pattern(pattern string) (*regexp.Regexp, error) {
+	if compiled, ok := s.patterns[pattern]; ok {
+		return compiled, nil
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema pattern %q: %v", pattern, err)
+	}
+	s.patterns[pattern] = compiled
+	return compiled, nil
+}
+
+// Validate a decoded document, errors are sorted by path
+func (s *JSONSchema) Validate(data interface{}) []JSONSchemaError {
+	errors := s.validate(s.root, data, "$", 0)
+	sort.SliceStable(errors, func(i, j int) bool {
+		return errors[i].Path < errors[j].Path
+	})
+	return errors
+}
+
+// Validate the content of a JSON document, syntax errors are reported at the root path
+func (s *JSONSchema) ValidateContent(content []byte) []JSONSchemaError {
+	data, err := DecodeJSON(content)
+	if err != nil {
+		return []JSONSchemaError{{Path: "$", Message: err.Error()}}
+	}
+	return s.Validate(data)
+}
+
+// Validate a config file against an embedded schema, returning one error listing every problem
+func ValidateConfigFile(path string, schemaName string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	schema, err := LoadJSONSchema(schemaName)
+	if err != nil {
+		return err
+	}
+	errors := schema.ValidateContent(content)
+	if len(errors) == 0 {
+		return nil
+	}
+	messages := make([]string, len(errors))
+	for i, e := range errors {
+		messages[i] = "  " + e.Error()
+	}
+	return fmt.Errorf("invalid %s:\n%s", path, strings.Join(messages, "\n"))
+}
+
+func (s *JSONSchema) validate(node interface{}, data interface{}, path string, depth int) []JSONSchemaError {
+	if depth > 64 {
+		return []JSONSchemaError{{Path: path, Message: "schema recursion is too deep"}}
+	}
+	switch schema := node.(type) {
+	case bool:
+		if !schema {
+			return []JSONSchemaError{{Path: path, Message: "no value is allowed"}}
+		}
+		return nil
+	case map[string]interface{}:
+		return s.validateObject(schema, data, path, depth)
+	}
+	return nil
+}
+
+func (s *JSONSchema) validateObject(schema map[string]interface{}, data interface{}, path string, depth int) []JSONSchemaError {
+	var errors []JSONSchemaError
+	failAt := func(path string, format string, args ...interface{}) {
+		errors = append(errors, JSONSchemaError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	fail := func(format string, args ...interface{}) {
+		failAt(path, format, args...)
+	}
+	child := func(node interface{}, data interface{}, path string) {
+		errors = append(errors, s.validate(node, data, path, depth+1)...)
+	}
+
+	// References to the root or its definitions
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := s.resolve(ref)
+		if err != nil {
+			fail("%v", err)
+		} else {
+			child(target, data, path)
+		}
+	}
+
+	// Type and values
+	if types, ok := schema["type"]; ok {
+		var allowed []string
+		switch value := types.(type) {
+		case string:
+			allowed = []string{value}
+		case []interface{}:
+			for _, item := range value {
+				if name, ok := item.(string); ok {
+					allowed = append(allowed, name)
+				}
+			}
+		}
+		actual := jsonType(data)
+		if !SliceContainsString(allowed, actual) && !(actual == "integer" && SliceContainsString(allowed, "number")) {
+			fail("expected %s, got %s", strings.Join(allowed, " or "), actual)
+			return errors
+		}
+	}
+	if values, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, value := range values {
+			if jsonEqual(value, data) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %s", jsonCompact(values))
+		}
+	}
+	if value, ok := schema["const"]; ok && !jsonEqual(value, data) {
+		fail("must be %s", jsonCompact(value))
+	}
+
+	switch value := data.(type) {
+	case map[string]interface{}:
+		s.validateProperties(schema, value, path, failAt, child)
+	case []interface{}:
+		s.validateItems(schema, value, path, fail, child)
+	case string:
+		length := utf8.RuneCountInString(value)
+		if minimum, ok := jsonSchemaNumber(schema["minLength"]); ok && float64(length) < minimum {
+			fail("must be at least %v characters", minimum)
+		}
+		if maximum, ok := jsonSchemaNumber(schema["maxLength"]); ok && float64(length) > maximum {
+			fail("must be at most %v characters", maximum)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if compiled, err := s.pattern(pattern); err != nil {
+				fail("%v", err)
+			} else if !compiled.MatchString(value) {
+				fail("must match pattern %s", pattern)
+			}
+		}
+	case json.Number, float64:
+		number, _ := jsonSchemaNumber(value)
+		if minimum, ok := jsonSchemaNumber(schema["minimum"]); ok && number < minimum {
+			fail("must be >= %v", minimum)
+		}
+		if maximum, ok := jsonSchemaNumber(schema["maximum"]); ok && number > maximum {
+			fail("must be <= %v", maximum)
+		}
+		if minimum, ok := jsonSchemaNumber(schema["exclusiveMinimum"]); ok && number <= minimum {
+			fail("must be > %v", minimum)
+		}
+		if maximum, ok := jsonSchemaNumber(schema["exclusiveMaximum"]); ok && number >= maximum {
+			fail("must be < %v", maximum)
+		}
+		if divisor, ok := jsonSchemaNumber(schema["multipleOf"]); ok && divisor > 0 {
+			if quotient := number / divisor; math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+				fail("must be a multiple of %v", divisor)
+			}
+		}
+	}
+
+	// Composition
+	if schemas, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range schemas {
+			child(sub, data, path)
+		}
+	}
+	if schemas, ok := schema["anyOf"].([]interface{}); ok {
+		valid := false
+		for _, sub := range schemas {
+			if len(s.validate(sub, data, path, depth+1)) == 0 {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			fail("must match at least one schema of anyOf")
+		}
+	}
+	if schemas, ok := schema["oneOf"].([]interface{}); ok {
+		matches := 0
+		for _, sub := range schemas {
+			if len(s.validate(sub, data, path, depth+1)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			fail("must match exactly one schema of oneOf, matched %d", matches)
+		}
+	}
+	if sub, ok := schema["not"]; ok && len(s.validate(sub, data, path, depth+1)) == 0 {
+		fail("must not match the schema of not")
+	}
+	if condition, ok := schema["if"]; ok {
+		if len(s.validate(condition, data, path, depth+1)) == 0 {
+			if then, ok := schema["then"]; ok {
+				child(then, data, path)
+			}
+		} else if otherwise, ok := schema["else"]; ok {
+			child(otherwise, data, path)
+		}
+	}
+	return errors
+}
+
+func (s *JSONSchema) validateProperties(schema map[string]interface{}, object map[string]interface{}, path string, failAt func(string, string, ...interface{}), child func(interface{}, interface{}, string)) {
+	fail := func(format string, args ...interface{}) {
+		failAt(path, format, args...)
+	}
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				if _, exists := object[name]; !exists {
+					fail("missing required property %q", name)
+				}
+			}
+		}
+	}
+	if minimum, ok := jsonSchemaNumber(schema["minProperties"]); ok && float64(len(object)) < minimum {
+		fail("must have at least %v properties", minimum)
+	}
+	if maximum, ok := jsonSchemaNumber(schema["maxProperties"]); ok && float64(len(object)) > maximum {
+		fail("must have at most %v properties", maximum)
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	patternProperties, _ := schema["patternProperties"].(map[string]interface{})
+	additional, hasAdditional := schema["additionalProperties"]
+	names, hasNames := schema["propertyNames"]
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		keyPath := path + strings.TrimPrefix(jsonPathJoin("x", key), "x")
+
+		if hasNames {
+			child(names, key, keyPath)
+		}
+		matched := false
+		if sub, ok := properties[key]; ok {
+			child(sub, object[key], keyPath)
+			matched = true
+		}
+		for pattern, sub := range patternProperties {
+			compiled, err := s.pattern(pattern)
+			if err != nil {
+				fail("%v", err)
+				continue
+			}
+			if compiled.MatchString(key) {
+				child(sub, object[key], keyPath)
+				matched = true
+			}
+		}
+		if !matched && hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				failAt(keyPath, "unknown property")
+				continue
+			}
+			child(additional, object[key], keyPath)
+		}
+	}
+}
+
+func (s *JSONSchema) validateItems(schema map[string]interface{}, array []interface{}, path string, fail func(string, ...interface{}), child func(interface{}, interface{}, string)) {
+	if minimum, ok := jsonSchemaNumber(schema["minItems"]); ok && float64(len(array)) < minimum {
+		fail("must have at least %v items", minimum)
+	}
+	if maximum, ok := jsonSchemaNumber(schema["maxItems"]); ok && float64(len(array)) > maximum {
+		fail("must have at most %v items", maximum)
+	}
+	if unique, ok := schema["uniqueItems"].(bool); ok && unique {
+		for i := range array {
+			for j := i + 1; j < len(array); j++ {
+				if jsonEqual(array[i], array[j]) {
+					fail("items %d and %d are equal", i, j)
+				}
+			}
+		}
+	}
+
+	prefix, _ := schema["prefixItems"].([]interface{})
+	for i, item := range array {
+		itemPath := path + "[" + strconv.Itoa(i) + "]"
+		if i < len(prefix) {
+			child(prefix[i], item, itemPath)
+		} else if items, ok := schema["items"]; ok {
+			child(items, item, itemPath)
+		}
+	}
+	if contains, ok := schema["contains"]; ok {
+		found := false
+		for _, item := range array {
+			if len(s.validate(contains, item, path, 0)) == 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must contain an item matching the schema of contains")
+		}
+	}
+}
+
+// Resolve a local reference such as "#" or "#/$defs/directory"
+func (s *JSONSchema) resolve(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref %s, only local references are supported", ref)
+	}
+	node := s.root
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/"), "/") {
+		if part == "" {
+			continue
+		}
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolved $ref %s", ref)
+		}
+		if node, ok = object[part]; !ok {
+			return nil, fmt.Errorf("unresolved $ref %s", ref)
+		}
+	}
+	return node, nil
+}
+
+// JSON Schema type of a decoded value, whole numbers are integers
+func jsonType(data interface{}) string {
+	switch value := data.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case json.Number, float64:
+		number, _ := jsonSchemaNumber(value)
+		if number == math.Trunc(number) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", data)
+}
+
+// Number of a schema keyword or value
+func jsonSchemaNumber(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case json.Number:
+		parsed, err := number.Float64()
+		return parsed, err == nil
+	case float64:
+		return number, true
+	}
+	return 0, false
+}
+
+// Equality of decoded values, numbers compare by value
+func jsonEqual(a interface{}, b interface{}) bool {
+	if x, ok := jsonSchemaNumber(a); ok {
+		y, ok := jsonSchemaNumber(b)
+		return ok && x == y
+	}
+	switch x := a.(type) {
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			if other, ok := y[key]; !ok || !jsonEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// Compact JSON of a value for messages
+func jsonCompact(value interface{}) string {
+	encoded, err := marshalJSON(value, "")
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+// Print schema errors as table or json
+func PrintJSONSchemaErrors(file string, errors []JSONSchemaError, format string) {
+	if format == "json" {
+		if errors == nil {
+			errors = []JSONSchemaError{}
+		}
+		PrintJSON(map[string]interface{}{"file": file, "valid": len(errors) == 0, "errors": errors})
+		return
+	}
+	if len(errors) == 0 {
+		fmt.Println("✅", file, "is valid")
+		return
+	}
+	var rows [][]string
+	for _, e := range errors {
+		rows = append(rows, []string{e.Path, e.Message})
+	}
+	fmt.Println("❌", file)
+	PrintTable([]string{"PATH", "ERROR"}, rows)
+}
@@ -0,0 +1,470 @@
+package library
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSON Difference of a path between two documents
+type JSONDifference struct {
+	Path   string      `json:"path"`
+	Status string      `json:"status"` // changed, added or removed
+	Old    interface{} `json:"old"`
+	New    interface{} `json:"new"`
+}
+
+// Encode a difference, the old value of an added path and the new value of a removed path are left out
+// while null values are kept
+func (d JSONDifference) MarshalJSON() ([]byte, error) {
+	output := struct {
+		Path   string       `json:"path"`
+		Status string       `json:"status"`
+		Old    *interface{} `json:"old,omitempty"`
+		New    *interface{} `json:"new,omitempty"`
+	}{Path: d.Path, Status: d.Status}
+	if d.Status != "added" {
+		output.Old = &d.Old
+	}
+	if d.Status != "removed" {
+		output.New = &d.New
+	}
+	return json.Marshal(output)
+}
+
+// JSON toolbox of the json mode
+func jsonToolbox(flags Flag) {
+	/** Pretty print or minify with sorted keys */
+	if *flags.Pretty || *flags.Minify {
+		data, err := ReadJSONFile(*flags.Path)
+		if err != nil {
+			fmt.Println("❌", err)
+			os.Exit(1)
+		}
+		indent := "  "
+		if *flags.Minify {
+			indent = ""
+		}
+		writeJSONOutput(data, indent, *flags.Output)
+	}
+
+	/** Validate JSON files */
+	if *flags.Validate {
+		files := []string{*flags.Path}
+		if info, err := os.Stat(*flags.Path); err == nil && info.IsDir() {
+			files, err = FileFilter{Exclude: *flags.Exclude, Ext: []string{".json"}}.CollectFiles(*flags.Path)
+			if err != nil {
+				fmt.Println("❌", err)
+				os.Exit(1)
+			}
+		}
+		var schema *JSONSchema
+		if *flags.Schema != "" {
+			var err error
+			schema, err = LoadJSONSchema(*flags.Schema)
+			if err != nil {
+				fmt.Println("❌", err)
+				os.Exit(1)
+			}
+		}
+		invalid := 0
+		for _, file := range files {
+			data, err := ReadJSONFile(file)
+			if err != nil {
+				fmt.Println("❌", err)
+				invalid++
+				continue
+			}
+			if schema != nil {
+				errors := schema.Validate(data)
+				if len(errors) > 0 || *flags.Format == "json" {
+					PrintJSONSchemaErrors(file, errors, *flags.Format)
+				}
+				if len(errors) > 0 {
+					invalid++
+				}
+			}
+		}
+		if invalid > 0 {
+			if *flags.Format != "json" {
+				fmt.Println("🐙 There are", invalid, "invalid of", len(files), "JSON files")
+			}
+			os.Exit(1)
+		}
+		if *flags.Format != "json" {
+			fmt.Println("✅", len(files), "valid JSON files")
+		}
+	}
+
+	/** Print an embedded schema */
+	if *flags.Schema != "" && !*flags.Validate {
+		content, ok := jsonSchemas[*flags.Schema]
+		if !ok {
+			fmt.Println("❌ Unknown schema", *flags.Schema, "("+strings.Join(JSONSchemaNames(), "|")+")")
+			os.Exit(1)
+		}
+		fmt.Println(content)
+	}
+
+	/** Extract values by path expression */
+	if *flags.Query != "" {
+		data, err := ReadJSONFile(*flags.Path)
+		if err != nil {
+			fmt.Println("❌", err)
+			os.Exit(1)
+		}
+		values, err := JSONQuery(data, *flags.Query)
+		if err != nil {
+			fmt.Println("❌", err)
+			os.Exit(1)
+		}
+		for _, value := range values {
+			if text, ok := value.(string); ok && *flags.Format != "json" {
+				fmt.Println(text)
+				continue
+			}
+			encoded, _ := marshalJSON(value, "")
+			fmt.Println(string(encoded))
+		}
+	}
+
+	/** Merge files, later files override earlier ones */
+	if *flags.Merge {
+		data, err := ReadJSONFile(*flags.Path)
+		if err != nil {
+			fmt.Println("❌", err)
+			os.Exit(1)
+		}
+		for _, path := range *flags.ComparePaths {
+			override, err := ReadJSONFile(path)
+			if err != nil {
+				fmt.Println("❌", err)
+				os.Exit(1)
+			}
+			data = MergeJSON(data, override)
+		}
+		writeJSONOutput(data, "  ", *flags.Output)
+	}
+
+	/** Structural diff of two files */
+	if *flags.Diff && len(*flags.ComparePaths) > 0 {
+		before, err := ReadJSONFile(*flags.Path)
+		if err != nil {
+			fmt.Println("❌", err)
+			os.Exit(1)
+		}
+		after, err := ReadJSONFile((*flags.ComparePaths)[0])
+		if err != nil {
+			fmt.Println("❌", err)
+			os.Exit(1)
+		}
+		PrintJSONDifferences(DiffJSON(before, after), *flags.Format)
+	}
+}
+
+// Read and decode a JSON file, "-" reads stdin. Numbers keep their original text.
+func ReadJSONFile(path string) (interface{}, error) {
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := DecodeJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return data, nil
+}
+
+// Decode a JSON document, syntax errors report the line and column
+func DecodeJSON(content []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var data interface{}
+	err := decoder.Decode(&data)
+	if err == nil {
+		if _, extra := decoder.Token(); extra != io.EOF {
+			err = fmt.Errorf("invalid character after top-level value")
+			return nil, fmt.Errorf("line %s: %v", jsonPosition(content, decoder.InputOffset()-1), err)
+		}
+		return data, nil
+	}
+
+	// The offset of a syntax error is after the offending character
+	if syntax, ok := err.(*json.SyntaxError); ok {
+		return nil, fmt.Errorf("line %s: %v", jsonPosition(content, syntax.Offset-1), err)
+	}
+	if err == io.EOF {
+		return nil, fmt.Errorf("empty document")
+	}
+	return nil, err
+}
+
+// Line and column of a byte offset, e.g. "3:14"
+func jsonPosition(content []byte, offset int64) string {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+	return fmt.Sprintf("%d:%d", line, column)
+}
+
+// Encode JSON with sorted keys and without HTML escaping, an empty indent minifies
+func marshalJSON(data interface{}, indent string) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// Write JSON to the output file or stdout
+func writeJSONOutput(data interface{}, indent string, output string) {
+	encoded, err := marshalJSON(data, indent)
+	if err != nil {
+		fmt.Println("❌", err)
+		os.Exit(1)
+	}
+	encoded = append(encoded, '\n')
+	if output == "" || output == "-" {
+		os.Stdout.Write(encoded)
+		return
+	}
+	if err := os.WriteFile(output, encoded, 0644); err != nil {
+		fmt.Println("❌", err)
+		os.Exit(1)
+	}
+	fmt.Println("✅ Written to", output)
+}
+
+// Split a path expression such as `config.build[0].name`, `plugins[*].slug` or `scripts["build:prod"]` into keys
+func parseJSONPath(expression string) ([]string, error) {
+	var keys []string
+	expression = strings.TrimPrefix(strings.TrimPrefix(expression, "$"), ".")
+	for len(expression) > 0 {
+		switch expression[0] {
+		case '.':
+			expression = expression[1:]
+		case '[':
+			end := strings.IndexByte(expression, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ in path expression")
+			}
+			key := expression[1:end]
+			if unquoted, err := strconv.Unquote(key); err == nil {
+				key = unquoted
+			} else if _, err := strconv.Atoi(key); err != nil && key != "*" {
+				return nil, fmt.Errorf("invalid index [%s] in path expression", key)
+			}
+			keys = append(keys, key)
+			expression = expression[end+1:]
+		default:
+			end := strings.IndexAny(expression, ".[")
+			if end < 0 {
+				end = len(expression)
+			}
+			keys = append(keys, expression[:end])
+			expression = expression[end:]
+		}
+	}
+	return keys, nil
+}
+
+// Values of a path expression, "*" selects every item of an array or every value of an object
+func JSONQuery(data interface{}, expression string) ([]interface{}, error) {
+	keys, err := parseJSONPath(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []interface{}{data}
+	for _, key := range keys {
+		var next []interface{}
+		for _, node := range nodes {
+			switch value := node.(type) {
+			case map[string]interface{}:
+				if key == "*" {
+					names := make([]string, 0, len(value))
+					for name := range value {
+						names = append(names, name)
+					}
+					sort.Strings(names)
+					for _, name := range names {
+						next = append(next, value[name])
+					}
+				} else if child, ok := value[key]; ok {
+					next = append(next, child)
+				}
+			case []interface{}:
+				if key == "*" {
+					next = append(next, value...)
+				} else if index, err := strconv.Atoi(key); err == nil {
+					if index < 0 {
+						index += len(value)
+					}
+					if index >= 0 && index < len(value) {
+						next = append(next, value[index])
+					}
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+// Merge an override into a document: objects are merged recursively, a null removes a key
+// and arrays and other values are replaced
+func MergeJSON(base interface{}, override interface{}) interface{} {
+	overrideObject, ok := override.(map[string]interface{})
+	if !ok {
+		return override
+	}
+	baseObject, ok := base.(map[string]interface{})
+	if !ok {
+		baseObject = make(map[string]interface{})
+	}
+
+	merged := make(map[string]interface{}, len(baseObject))
+	for key, value := range baseObject {
+		merged[key] = value
+	}
+	for key, value := range overrideObject {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = MergeJSON(merged[key], value)
+	}
+	return merged
+}
+
+// Structural diff of two documents, sorted by path
+func DiffJSON(before interface{}, after interface{}) []JSONDifference {
+	var differences []JSONDifference
+	var walk func(path string, before interface{}, after interface{})
+	walk = func(path string, before interface{}, after interface{}) {
+		oldObject, oldIsObject := before.(map[string]interface{})
+		newObject, newIsObject := after.(map[string]interface{})
+		if oldIsObject && newIsObject {
+			keys := make(map[string]bool)
+			for key := range oldObject {
+				keys[key] = true
+			}
+			for key := range newObject {
+				keys[key] = true
+			}
+			for key := range keys {
+				child := jsonPathJoin(path, key)
+				oldValue, inOld := oldObject[key]
+				newValue, inNew := newObject[key]
+				switch {
+				case !inNew:
+					differences = append(differences, JSONDifference{Path: child, Status: "removed", Old: oldValue})
+				case !inOld:
+					differences = append(differences, JSONDifference{Path: child, Status: "added", New: newValue})
+				default:
+					walk(child, oldValue, newValue)
+				}
+			}
+			return
+		}
+
+		oldArray, oldIsArray := before.([]interface{})
+		newArray, newIsArray := after.([]interface{})
+		if oldIsArray && newIsArray {
+			for i := 0; i < len(oldArray) || i < len(newArray); i++ {
+				child := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(newArray):
+					differences = append(differences, JSONDifference{Path: child, Status: "removed", Old: oldArray[i]})
+				case i >= len(oldArray):
+					differences = append(differences, JSONDifference{Path: child, Status: "added", New: newArray[i]})
+				default:
+					walk(child, oldArray[i], newArray[i])
+				}
+			}
+			return
+		}
+
+		// Numbers are compared by value, 1, 1.0 and 1e0 are equal
+		if !jsonEqual(before, after) {
+			differences = append(differences, JSONDifference{Path: path, Status: "changed", Old: before, New: after})
+		}
+	}
+	walk("", before, after)
+
+	sort.SliceStable(differences, func(i, j int) bool {
+		return differences[i].Path < differences[j].Path
+	})
+	return differences
+}
+
+// Join a path and a key, keys that aren't identifiers are quoted
+func jsonPathJoin(path string, key string) string {
+	identifier := key != ""
+	for i, r := range key {
+		if !(r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && (r >= '0' && r <= '9' || r == '-')) {
+			identifier = false
+			break
+		}
+	}
+	if !identifier {
+		return path + "[" + strconv.Quote(key) + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Print JSON differences as table or json
+func PrintJSONDifferences(differences []JSONDifference, format string) {
+	if format == "json" {
+		if differences == nil {
+			differences = []JSONDifference{}
+		}
+		PrintJSON(differences)
+		return
+	}
+
+	value := func(v interface{}, present bool) string {
+		if !present {
+			return ""
+		}
+		encoded, _ := marshalJSON(v, "")
+		if len([]rune(string(encoded))) > 50 {
+			return string([]rune(string(encoded))[:47]) + "..."
+		}
+		return string(encoded)
+	}
+	var rows [][]string
+	for _, difference := range differences {
+		path := difference.Path
+		if path == "" {
+			path = "$"
+		}
+		rows = append(rows, []string{difference.Status, path, value(difference.Old, difference.Status != "added"), value(difference.New, difference.Status != "removed")})
+	}
+	PrintTable([]string{"STATUS", "PATH", "OLD", "NEW"}, rows)
+	fmt.Println("🐙 There are", len(differences), "differences")
+}
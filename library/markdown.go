@@ -1,18 +1,34 @@
 package library
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
+	"unicode"
 )
 
+// Markdown Heading
+type MarkdownHeading struct {
+	Level  int    `json:"level"`
+	Text   string `json:"text"`
+	Line   int    `json:"line"`
+	Anchor string `json:"anchor"`
+}
+
+// ATX heading, e.g. "## Install from source ##"
+var markdownHeadingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+
+// Opening or closing code fence, e.g. "```go" or "~~~"
+var markdownFencePattern = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+
 // Initiate Markdown Function
 func InitiateMarkdownFunction(flags Flag) {
 	/** Remove Link */
-	if *flags.Markdown && *flags.RemoveLink {
+	if *flags.Markdown && *flags.RemoveLink && !*flags.Links {
 		if *flags.Path == "" {
 			CurrentDirectory, _ := os.Getwd()
 			*flags.Path = CurrentDirectory
@@ -21,142 +37,423 @@ func InitiateMarkdownFunction(flags Flag) {
 		WriteFile(*flags.Path, MarkdownRemoveLink(string(content)))
 		fmt.Println("✅ Link removed")
 	}
-	/** Remove Link */
+	/** File Tree */
 	if *flags.Markdown && *flags.Tree {
-		fileTree := MarkdownGenerateFileTree(*flags.Path, *flags.Filename)
-		fmt.Println(fileTree)
+		options := MarkdownTreeOptions{
+			Ignore:    *flags.Filename,
+			MaxDepth:  *flags.Level,
+			DirsFirst: *flags.DirsFirst,
+			Style:     *flags.Style,
+		}
+		if *flags.Output != "" {
+			changed, err := MarkdownUpdateTreeFile(*flags.Output, *flags.Path, *flags.Heading, options)
+			if err != nil {
+				fmt.Println("❌ Error updating file tree:", err)
+				os.Exit(1)
+			}
+			if changed {
+				fmt.Println("✅ File tree updated:", *flags.Output)
+			} else {
+				fmt.Println("✅ File tree is up to date:", *flags.Output)
+			}
+			return
+		}
+		tree, err := MarkdownFileTree(*flags.Path, options)
+		if err != nil {
+			fmt.Println("❌ Error generating file tree:", err)
+			os.Exit(1)
+		}
+		fmt.Print(tree)
+	}
+	/** List, Strip or Rewrite Links */
+	if *flags.Markdown && *flags.Links {
+		options := LinkRewriteOptions{
+			Strip:    *flags.RemoveLink,
+			From:     *flags.From,
+			To:       *flags.To,
+			Relative: *flags.Relative,
+			BaseURL:  *flags.Url,
+			Hostname: *flags.Hostname,
+		}
+		for _, pattern := range *flags.Pattern {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				fmt.Println("❌ Invalid pattern:", err)
+				return
+			}
+			options.Patterns = append(options.Patterns, re)
+		}
+		if options.Strip || options.From != "" || options.Relative {
+			if err := MarkdownRewriteLinksInPath(*flags.Path, *flags.Exclude, options, *flags.DryRun); err != nil {
+				fmt.Println("❌ Error rewriting links:", err)
+			}
+		} else {
+			links, err := MarkdownListLinks(*flags.Path, *flags.Exclude, options)
+			if err != nil {
+				fmt.Println("❌ Error listing links:", err)
+				return
+			}
+			PrintFileLinks(links, *flags.Format)
+		}
+	}
+	/** Table of Contents */
+	if *flags.Markdown && *flags.Toc {
+		if *flags.DryRun {
+			fmt.Print(MarkdownGenerateToc(string(ReadFile(*flags.Path)), *flags.MinDepth, *flags.MaxDepth))
+			return
+		}
+		changed, err := MarkdownUpdateTocFile(*flags.Path, *flags.MinDepth, *flags.MaxDepth, *flags.Check)
+		if err != nil {
+			fmt.Println("❌ Error updating table of contents:", err)
+			os.Exit(1)
+		}
+		if *flags.Check && changed {
+			fmt.Println("❌ Table of contents is outdated:", *flags.Path)
+			os.Exit(1)
+		} else if changed {
+			fmt.Println("✅ Table of contents updated:", *flags.Path)
+		} else {
+			fmt.Println("✅ Table of contents is up to date:", *flags.Path)
+		}
+	}
+	/** Export Static HTML Site */
+	if *flags.Markdown && *flags.Export {
+		options := MarkdownExportOptions{
+			Output:   *flags.Output,
+			Template: *flags.Template,
+			MinDepth: *flags.MinDepth,
+			MaxDepth: *flags.MaxDepth,
+			Exclude:  *flags.Exclude,
+		}
+		pages, assets, err := MarkdownExportSite(*flags.Path, options)
+		if err != nil {
+			fmt.Println("❌ Error exporting site:", err)
+			os.Exit(1)
+		}
+		fmt.Println("✅ Exported", pages, "pages and", assets, "assets to", *flags.Output)
+	}
+	/** Note Link Graph */
+	if *flags.Markdown && *flags.Graph {
+		graph, err := NewMarkdownGraph(*flags.Path, *flags.Exclude)
+		if err != nil {
+			fmt.Println("❌ Error building graph:", err)
+			os.Exit(1)
+		}
+		switch {
+		case *flags.Backlinks != "":
+			note, ok := graph.FindNote(*flags.Backlinks)
+			if !ok {
+				fmt.Println("❌ Note not found:", *flags.Backlinks)
+				os.Exit(1)
+			}
+			PrintBacklinks(note, graph.Backlinks(note), *flags.Format)
+		case *flags.Orphans:
+			PrintNotes(graph.Orphans(), *flags.Format, "orphan notes")
+		case *flags.DeadEnds:
+			PrintNotes(graph.DeadEnds(), *flags.Format, "dead-end notes")
+		default:
+			PrintMarkdownGraph(graph, *flags.Format)
+		}
+	}
+	/** Front Matter Query */
+	if *flags.Markdown && *flags.Query != "" {
+		query, err := ParseMarkdownQuery(*flags.Query)
+		if err != nil {
+			fmt.Println("❌", err)
+			os.Exit(1)
+		}
+		notes, warnings, err := ReadMarkdownNotes(*flags.Path, *flags.Exclude)
+		if err != nil {
+			fmt.Println("❌ Error reading notes:", err)
+			os.Exit(1)
+		}
+		for _, warning := range warnings {
+			fmt.Fprintln(os.Stderr, "⚠️ Invalid front matter", warning)
+		}
+		// The index itself is not a note of the query
+		if *flags.Output != "" {
+			var kept []MarkdownNote
+			for _, note := range notes {
+				if !IsSamePath(filepath.Join(*flags.Path, filepath.FromSlash(note.File)), *flags.Output) {
+					kept = append(kept, note)
+				}
+			}
+			notes = kept
+		}
+		notes = query.Run(notes)
+		if *flags.Output != "" {
+			WriteFile(*flags.Output, MarkdownQueryIndex(query, query.GroupNotes(notes), *flags.Output, *flags.Path))
+			fmt.Println("✅ Index with", len(notes), "notes written to", *flags.Output)
+		} else {
+			PrintMarkdownQuery(query, notes, *flags.Format)
+		}
+	}
+	/** Format or Lint */
+	if *flags.Markdown && (*flags.Fmt || *flags.Lint) {
+		config, err := ReadMarkdownLintConfig(*flags.Config)
+		if err != nil {
+			fmt.Println("❌", err)
+			os.Exit(1)
+		}
+		issues, err := MarkdownFormatPath(*flags.Path, *flags.Exclude, config, *flags.Lint, *flags.Check, *flags.DryRun)
+		if err != nil {
+			fmt.Println("❌ Error formatting Markdown:", err)
+			os.Exit(1)
+		}
+		if *flags.Lint || *flags.Check {
+			PrintMarkdownLintIssues(issues, *flags.Format)
+			if len(issues) > 0 {
+				os.Exit(1)
+			}
+		} else if *flags.DryRun {
+			fmt.Println("🔍 Dry run completed.")
+		}
+	}
+	/** Tasks */
+	if *flags.Markdown && *flags.Tasks {
+		filter := MarkdownTaskFilter{
+			Status:    *flags.Status,
+			Tags:      *flags.Tag,
+			DateStart: parseDateOr(*flags.DateStart, time.Time{}),
+			DateEnd:   parseDateOr(*flags.DateEnd, time.Time{}),
+		}
+		tasks, err := CollectMarkdownTasks(*flags.Path, *flags.Exclude, *flags.Output, filter)
+		if err != nil {
+			fmt.Println("❌ Error collecting tasks:", err)
+			os.Exit(1)
+		}
+		if *flags.Output != "" {
+			WriteFile(*flags.Output, MarkdownTasksReport(tasks, *flags.Path, *flags.Output, time.Now()))
+			fmt.Println("✅ Report with", len(tasks), "tasks written to", *flags.Output)
+		} else {
+			PrintMarkdownTasks(tasks, *flags.Format)
+		}
+	}
+	/** Check Internal Links */
+	if *flags.Markdown && *flags.CheckLinks {
+		broken, err := MarkdownCheckLinks(*flags.Path, *flags.Exclude, *flags.Suggest)
+		if err != nil {
+			fmt.Println("❌ Error checking links:", err)
+			os.Exit(1)
+		}
+		PrintBrokenLinks(broken, *flags.Format)
+		if len(broken) > 0 {
+			os.Exit(1)
+		}
 	}
 	// Extract Heading Number
-	if *flags.Markdown && *flags.Heading != "" && strings.HasPrefix(*flags.Heading, "#") {
+	if *flags.Markdown && *flags.Heading != "" && strings.Trim(*flags.Heading, "#") == "" {
 		headings, _ := ExtractHeadings(*flags.Path, *flags.Heading)
 		for _, heading := range headings {
 			fmt.Println(heading)
 		}
 	}
 	// Extract Markdown content
-	if *flags.Markdown && *flags.Heading != "" {
-		content, _ := ExtractContentByHeading(*flags.Path, *flags.Heading)
-		fmt.Println(content)
-	}
-}
-
-// Remove Link from Markdown File
-func MarkdownRemoveLink(markdown string) string {
-	// Use the regular expression to search for all links
-	pattern := regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
-	links := pattern.FindAllStringSubmatch(markdown, -1)
-
-	// Replace each link with its label (the text between the square brackets)
-	for _, link := range links {
-		markdown = strings.Replace(markdown, link[0], link[1], -1)
-	}
-
-	return markdown
-}
-
-// Generate File Tree from Directory
-func MarkdownGenerateFileTree(path string, ignore []string) string {
-	if path == "" {
-		CurrentDirectory, _ := os.Getwd()
-		path = CurrentDirectory
-	}
-
-	// Ignore the files and directories
-	ignore = append(ignore, ".git", ".github", ".vscode", ".idea", ".obsidian", ".gitignore", ".gitkeep", ".DS_Store")
-
-	// Read the directory
-	files, err := os.ReadDir(path)
-	if err != nil {
-		return ""
+	if *flags.Markdown && *flags.Heading != "" && strings.Trim(*flags.Heading, "#") != "" && *flags.Section == "" && !*flags.Tree {
+		content, err := ExtractContentByHeading(*flags.Path, *flags.Heading)
+		if err != nil {
+			fmt.Println("❌", err)
+			os.Exit(1)
+		}
+		fmt.Print(content)
 	}
-
-	tree := ""
-	indent := strings.Repeat("    ", strings.Count(path, string(os.PathSeparator)))
-
-	for _, file := range files {
-		if SliceContainsString(ignore, file.Name()) {
-			continue
+	/** Edit Section by Heading Path */
+	if *flags.Markdown && *flags.Heading != "" && *flags.Section != "" {
+		var text []byte
+		var err error
+		if *flags.Input == "" || *flags.Input == "-" {
+			text, err = io.ReadAll(os.Stdin)
+		} else {
+			text, err = os.ReadFile(*flags.Input)
 		}
-
-		if file.IsDir() {
-			subtree := MarkdownGenerateFileTree(filepath.Join(path, file.Name()), ignore)
-			tree += fmt.Sprintf("%s- %s\n", indent, file.Name())
-			tree += subtree
+		if err != nil {
+			fmt.Println("❌ Error reading input:", err)
+			os.Exit(1)
+		}
+		content := string(ReadFile(*flags.Path))
+		updated, err := MarkdownEditSection(content, *flags.Heading, *flags.Section, string(text))
+		if err != nil {
+			fmt.Println("❌", err)
+			os.Exit(1)
+		}
+		if *flags.DryRun {
+			fmt.Print(UnifiedDiff(splitLines(content), splitLines(updated), *flags.Path, *flags.Path, 3))
+			fmt.Println("🔍 Dry run completed.")
+		} else if updated != content {
+			WriteFile(*flags.Path, updated)
+			fmt.Println("✅ Section updated:", *flags.Heading)
 		} else {
-			link := filepath.Join(strings.Split(path, string(os.PathSeparator))...)
-			slugifyPath := Slugify(link + string(os.PathSeparator) + file.Name())
-			tree += fmt.Sprintf("%s- [%s](%s)\n", indent, file.Name(), slugifyPath)
+			fmt.Println("✅ Section is up to date:", *flags.Heading)
 		}
 	}
-
-	return tree
 }
 
 // Extract heading
 func ExtractHeadings(filePath, heading string) ([]string, error) {
-	file, err := os.Open(filePath)
+	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
 	var headings []string
-	scanner := bufio.NewScanner(file)
-	re := regexp.MustCompile(fmt.Sprintf(`^%s\s+(.*)$`, regexp.QuoteMeta(heading)))
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		match := re.FindStringSubmatch(line)
-		if len(match) > 1 {
-			headings = append(headings, match[1])
+	for _, h := range ParseMarkdownHeadings(string(content)) {
+		if h.Level == len(heading) {
+			headings = append(headings, h.Text)
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-
 	return headings, nil
 }
 
-// Extract markdown content by heading
+// Extract markdown content by heading path, e.g. "Installation > Install from source", subsections included
 func ExtractContentByHeading(filePath, heading string) (string, error) {
-	file, err := os.Open(filePath)
+	content, err := os.ReadFile(filePath)
 	if err != nil {
-		fmt.Println("Error opening file:", err)
 		return "", err
 	}
-	defer file.Close()
+	return MarkdownSectionContent(string(content), heading)
+}
+
+// Split YAML front matter from the body of a Markdown document, front matter is empty when missing
+func MarkdownFrontMatter(content string) (string, string) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return "", content
+	}
+	for i := 1; i < len(lines); i++ {
+		if trimmed := strings.TrimSpace(lines[i]); trimmed == "---" || trimmed == "..." {
+			return strings.Join(lines[1:i], "\n"), strings.Join(lines[i+1:], "\n")
+		}
+	}
+	return "", content
+}
+
+// Number of front matter lines, YAML between "---" (or "..." closing) and TOML between "+++", 0 when missing
+func markdownFrontMatterLines(lines []string) int {
+	if len(lines) == 0 {
+		return 0
+	}
+	opening := strings.TrimSpace(lines[0])
+	if opening != "---" && opening != "+++" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == opening || opening == "---" && trimmed == "..." {
+			return i + 1
+		}
+	}
+	return 0
+}
 
-	var content strings.Builder
-	scanner := bufio.NewScanner(file)
-	found := false
+// Mark lines that belong to fenced code blocks (fence lines included) or YAML and TOML front matter
+func MarkdownCodeLines(lines []string) []bool {
+	code := make([]bool, len(lines))
 
-	for scanner.Scan() {
-		line := scanner.Text()
+	// Front matter
+	start := markdownFrontMatterLines(lines)
+	for i := 0; i < start; i++ {
+		code[i] = true
+	}
 
-		// Check for headings
-		if strings.HasPrefix(line, "#") {
-			// Trim leading spaces and hash symbols
-			currentHeading := strings.TrimLeft(line, "# ")
-			if currentHeading == heading {
-				found = true
-				continue // Skip the heading line itself
+	fence := ""
+	for i := start; i < len(lines); i++ {
+		match := markdownFencePattern.FindStringSubmatch(lines[i])
+		if fence == "" {
+			if match != nil {
+				fence = match[1]
+				code[i] = true
 			}
+			continue
+		}
+		code[i] = true
+		// Closing fence uses the same character and is at least as long
+		if match != nil && match[1][0] == fence[0] && len(match[1]) >= len(fence) && strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(lines[i]), match[1][:1])) == "" {
+			fence = ""
 		}
+	}
+	return code
+}
 
-		if found {
-			// Append content until next heading or end of file
-			if strings.HasPrefix(line, "#") {
-				break // Stop if a new heading is found
-			}
-			fmt.Fprintln(&content, line)
+// Parse ATX headings of a Markdown document, skipping code blocks and front matter
+func ParseMarkdownHeadings(content string) []MarkdownHeading {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	code := MarkdownCodeLines(lines)
+	slugger := NewMarkdownSlugger()
+
+	var headings []MarkdownHeading
+	for i, line := range lines {
+		if code[i] {
+			continue
 		}
+		match := markdownHeadingPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		text := strings.TrimSpace(match[2])
+		headings = append(headings, MarkdownHeading{
+			Level:  len(match[1]),
+			Text:   text,
+			Line:   i + 1,
+			Anchor: slugger.Slug(text),
+		})
 	}
+	return headings
+}
 
-	if err := scanner.Err(); err != nil {
-		return "", err
+// Markdown Slugger generates GitHub compatible anchors with duplicate suffixes
+type MarkdownSlugger struct {
+	seen map[string]int
+}
+
+// Create a new Markdown Slugger
+func NewMarkdownSlugger() *MarkdownSlugger {
+	return &MarkdownSlugger{seen: make(map[string]int)}
+}
+
+// Slug returns a unique anchor, duplicates get "-1", "-2", ... suffixes
+func (s *MarkdownSlugger) Slug(text string) string {
+	original := MarkdownAnchor(text)
+	slug := original
+	for {
+		if _, exists := s.seen[slug]; !exists {
+			break
+		}
+		s.seen[original]++
+		slug = fmt.Sprintf("%s-%d", original, s.seen[original])
 	}
+	s.seen[slug] = 0
+	return slug
+}
 
-	return content.String(), nil
+// Inline Markdown formatting removed before computing anchors
+var (
+	markdownInlineLinkPattern = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	markdownInlineRefPattern  = regexp.MustCompile(`!?\[([^\]]*)\]\[[^\]]*\]`)
+	markdownHTMLTagPattern    = regexp.MustCompile(`<[^>]+>`)
+)
+
+// Plain text of a heading, without links, code, emphasis and HTML
+func MarkdownPlainText(text string) string {
+	text = markdownInlineLinkPattern.ReplaceAllString(text, "$1")
+	text = markdownInlineRefPattern.ReplaceAllString(text, "$1")
+	text = markdownHTMLTagPattern.ReplaceAllString(text, "")
+	text = strings.NewReplacer("`", "", "**", "", "__", "", "*", "", "~~", "").Replace(text)
+	return strings.TrimSpace(text)
+}
+
+// GitHub compatible anchor of a heading text
+func MarkdownAnchor(text string) string {
+	text = strings.ToLower(MarkdownPlainText(text))
+
+	var anchor strings.Builder
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r) || r == '-' || r == '_':
+			anchor.WriteRune(r)
+		case r == ' ':
+			anchor.WriteRune('-')
+		}
+	}
+	return anchor.String()
 }
@@ -0,0 +1,317 @@
+package library
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Broken Markdown Link
+type BrokenLink struct {
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Kind       string `json:"kind"`
+	Target     string `json:"target"`
+	Reason     string `json:"reason"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Markdown Vault indexes the files of a directory for link resolution
+type MarkdownVault struct {
+	Root     string
+	Files    []string            // Relative slash paths of every file
+	Notes    []string            // Relative slash paths of Markdown files
+	Anchors  map[string][]string // Heading anchors per Markdown file
+	Headings map[string][]string // Heading texts per Markdown file
+	byName   map[string][]string // Lowercase name (without .md for notes) to relative paths
+	exists   map[string]bool
+}
+
+// Default directories ignored in a vault
+var markdownVaultIgnore = []string{".git", ".github", ".obsidian", ".trash", "node_modules"}
+
+// Index a directory of Markdown notes and attachments
+func NewMarkdownVault(root string, exclude []string) (*MarkdownVault, error) {
+	vault := &MarkdownVault{
+		Root:     root,
+		Anchors:  make(map[string][]string),
+		Headings: make(map[string][]string),
+		byName:   make(map[string][]string),
+		exists:   make(map[string]bool),
+	}
+	filter := FileFilter{Exclude: exclude}
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != root && (SliceContainsString(markdownVaultIgnore, info.Name()) || filter.Excluded(path)) {
+				return filepath.SkipDir
+			}
+		} else if filter.Excluded(path) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		vault.exists[rel] = true
+		if info.IsDir() {
+			return nil
+		}
+
+		vault.Files = append(vault.Files, rel)
+		name := strings.ToLower(filepath.Base(rel))
+		vault.byName[name] = append(vault.byName[name], rel)
+
+		if IsMarkdownFile(rel) {
+			vault.Notes = append(vault.Notes, rel)
+			vault.byName[strings.TrimSuffix(name, filepath.Ext(name))] = append(vault.byName[strings.TrimSuffix(name, filepath.Ext(name))], rel)
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			for _, heading := range ParseMarkdownHeadings(string(content)) {
+				vault.Anchors[rel] = append(vault.Anchors[rel], heading.Anchor)
+				vault.Headings[rel] = append(vault.Headings[rel], heading.Text)
+			}
+		}
+		return nil
+	})
+
+	sort.Strings(vault.Files)
+	sort.Strings(vault.Notes)
+	return vault, err
+}
+
+// Check if a file is Markdown
+func IsMarkdownFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// Resolve a wikilink target (without heading) to a relative path, like Obsidian:
+// paths are relative to the vault root, bare names match any file with that name.
+func (v *MarkdownVault) ResolveWikilink(target string) (string, bool) {
+	target = strings.TrimSpace(strings.TrimPrefix(target, "/"))
+	lower := strings.ToLower(target)
+
+	// Path from the vault root, with or without extension
+	if strings.Contains(target, "/") {
+		for _, candidate := range []string{target, target + ".md"} {
+			if v.exists[candidate] {
+				return candidate, true
+			}
+		}
+		for _, file := range v.Files {
+			if strings.EqualFold(file, target) || strings.EqualFold(file, target+".md") || strings.HasSuffix(strings.ToLower(file), "/"+lower+".md") {
+				return file, true
+			}
+		}
+		return "", false
+	}
+
+	// Bare name, shortest path wins
+	candidates := v.byName[lower]
+	if len(candidates) == 0 {
+		return "", false
+	}
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if strings.Count(candidate, "/") < strings.Count(best, "/") {
+			best = candidate
+		}
+	}
+	return best, true
+}
+
+// Resolve a relative link target from a file to a relative vault path
+func (v *MarkdownVault) ResolveRelative(from string, target string) (string, bool) {
+	var rel string
+	if strings.HasPrefix(target, "/") {
+		rel = strings.TrimPrefix(target, "/")
+	} else {
+		rel = filepath.ToSlash(filepath.Join(filepath.Dir(from), target))
+	}
+	rel = strings.TrimSuffix(filepath.ToSlash(filepath.Clean(rel)), "/")
+	if rel == "." {
+		rel = ""
+	}
+	if rel == "" || v.exists[rel] {
+		return rel, true
+	}
+
+	// Not indexed (excluded or outside the vault) but present on disk
+	if _, err := os.Stat(filepath.Join(v.Root, filepath.FromSlash(rel))); err == nil {
+		return rel, true
+	}
+	return rel, false
+}
+
+// Check if a note has a heading matching the anchor, by slug or by heading text (wikilinks)
+func (v *MarkdownVault) HasAnchor(note string, anchor string) bool {
+	anchor = strings.TrimPrefix(anchor, "#")
+	if anchor == "" || strings.HasPrefix(anchor, "^") {
+		return true
+	}
+	wanted := MarkdownAnchor(anchor)
+	for _, existing := range v.Anchors[note] {
+		if existing == anchor || existing == wanted {
+			return true
+		}
+	}
+	for _, heading := range v.Headings[note] {
+		if strings.EqualFold(strings.TrimSpace(heading), strings.TrimSpace(anchor)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Suggest the closest existing file for a broken target
+func (v *MarkdownVault) SuggestFile(target string) string {
+	name := strings.ToLower(filepath.Base(target))
+	name = strings.TrimSuffix(name, ".md")
+
+	best, bestDistance := "", -1
+	for _, file := range v.Files {
+		candidate := strings.ToLower(filepath.Base(file))
+		candidate = strings.TrimSuffix(candidate, ".md")
+		distance := LevenshteinDistance(name, candidate)
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = file, distance
+		}
+	}
+
+	// Only suggest reasonably close names
+	if bestDistance < 0 || bestDistance > len(name)/2+1 {
+		return ""
+	}
+	return best
+}
+
+// Suggest the closest existing anchor in a note
+func (v *MarkdownVault) SuggestAnchor(note string, anchor string) string {
+	wanted := MarkdownAnchor(anchor)
+	best, bestDistance := "", -1
+	for _, existing := range v.Anchors[note] {
+		distance := LevenshteinDistance(wanted, existing)
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = existing, distance
+		}
+	}
+	if bestDistance < 0 || bestDistance > len(wanted)/2+1 {
+		return ""
+	}
+	return "#" + best
+}
+
+// Check all internal links of the Markdown files in a directory
+func MarkdownCheckLinks(root string, exclude []string, suggest bool) ([]BrokenLink, error) {
+	vault, err := NewMarkdownVault(root, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var broken []BrokenLink
+	for _, note := range vault.Notes {
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(note)))
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range ParseMarkdownLinks(string(content)) {
+			if result := vault.checkLink(note, link, suggest); result != nil {
+				broken = append(broken, *result)
+			}
+		}
+	}
+	return broken, nil
+}
+
+// Check a single link, returning nil when it resolves
+func (v *MarkdownVault) checkLink(note string, link MarkdownLink, suggest bool) *BrokenLink {
+	result := &BrokenLink{File: note, Line: link.Line, Kind: link.Kind, Target: link.Target}
+
+	// Reference links without definition
+	if link.Kind == LinkReference && link.Target == "" {
+		result.Target = "[" + link.Label + "]"
+		result.Reason = "undefined reference"
+		return result
+	}
+	if link.Kind == LinkReference || link.Target == "" || IsExternalLink(link.Target) {
+		return nil
+	}
+
+	// Split target and heading
+	target, anchor := link.Target, ""
+	if hash := strings.Index(target, "#"); hash >= 0 {
+		target, anchor = target[:hash], target[hash+1:]
+	}
+
+	var resolved string
+	var ok bool
+	if link.Kind == LinkWikilink || link.Kind == LinkEmbed {
+		if target == "" {
+			resolved, ok = note, true
+		} else {
+			resolved, ok = v.ResolveWikilink(target)
+		}
+	} else {
+		if decoded, err := url.PathUnescape(target); err == nil {
+			target = decoded
+		}
+		if decoded, err := url.PathUnescape(anchor); err == nil {
+			anchor = decoded
+		}
+		if target == "" {
+			resolved, ok = note, true
+		} else {
+			resolved, ok = v.ResolveRelative(note, target)
+		}
+	}
+
+	if !ok {
+		result.Reason = "file not found"
+		if suggest {
+			result.Suggestion = v.SuggestFile(target)
+		}
+		return result
+	}
+	if anchor != "" && IsMarkdownFile(resolved) && !v.HasAnchor(resolved, anchor) {
+		result.Reason = "heading not found"
+		if suggest {
+			result.Suggestion = v.SuggestAnchor(resolved, anchor)
+		}
+		return result
+	}
+	return nil
+}
+
+// Print broken links
+func PrintBrokenLinks(broken []BrokenLink, format string) {
+	if format == "json" {
+		if broken == nil {
+			broken = []BrokenLink{}
+		}
+		PrintJSON(broken)
+		return
+	}
+
+	for _, link := range broken {
+		fmt.Printf("❌ %s:%d: %s (%s)\n", link.File, link.Line, link.Target, link.Reason)
+		if link.Suggestion != "" {
+			fmt.Println("   💡 Did you mean", link.Suggestion)
+		}
+	}
+	if len(broken) == 0 {
+		fmt.Println("✅ No broken links found")
+	} else {
+		fmt.Println("🐙 There are", len(broken), "broken links")
+	}
+}
@@ -0,0 +1,444 @@
+package library
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// Markdown Export Options
+type MarkdownExportOptions struct {
+	Output   string
+	Template string // Template file, the default template is used when empty
+	MinDepth int    // Heading levels of the page table of contents
+	MaxDepth int
+	Exclude  []string
+}
+
+// Data passed to the page template
+type MarkdownPageData struct {
+	Title   string
+	Path    string // Page path relative to the site root
+	Root    string // Relative path from the page to the site root, e.g. "../"
+	Content template.HTML
+	Toc     template.HTML
+	Nav     template.HTML
+}
+
+// Exported page of a note
+type markdownPage struct {
+	Source string // Relative slash path of the note
+	Output string // Relative slash path of the HTML page
+	Title  string
+}
+
+// Default page template, override with --template
+const MarkdownExportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { margin: 0; display: flex; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.6; color: #24292f; }
+nav.sidebar { width: 260px; flex-shrink: 0; padding: 1rem; background: #f6f8fa; border-right: 1px solid #d0d7de; min-height: 100vh; box-sizing: border-box; }
+nav.sidebar ul { list-style: none; padding-left: 1rem; margin: 0; }
+nav.sidebar > ul { padding-left: 0; }
+nav.sidebar a { color: #0969da; text-decoration: none; }
+nav.sidebar a.active { font-weight: bold; }
+nav.sidebar .dir { font-weight: 600; }
+main { flex: 1; max-width: 860px; padding: 1rem 2rem; }
+aside.toc { width: 220px; flex-shrink: 0; padding: 1rem; font-size: 0.9em; }
+aside.toc ul { list-style: none; padding-left: 0; }
+aside.toc .toc-h3 { padding-left: 1rem; } aside.toc .toc-h4 { padding-left: 2rem; } aside.toc .toc-h5, aside.toc .toc-h6 { padding-left: 3rem; }
+pre { background: #f6f8fa; padding: 1rem; overflow: auto; }
+code { background: #f6f8fa; padding: 0.1em 0.3em; }
+pre code { padding: 0; }
+table { border-collapse: collapse; } th, td { border: 1px solid #d0d7de; padding: 0.3em 0.8em; }
+img { max-width: 100%; }
+.broken-link { color: #cf222e; text-decoration: line-through; }
+</style>
+</head>
+<body>
+<nav class="sidebar">{{.Nav}}</nav>
+<main>
+{{.Content}}
+</main>
+{{if .Toc}}<aside class="toc">{{.Toc}}</aside>{{end}}
+</body>
+</html>
+`
+
+// Heading IDs generated with the same anchors as the table of contents and link checker
+type markdownHeadingIDs struct {
+	slugger *MarkdownSlugger
+}
+
+func (ids *markdownHeadingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	return []byte(ids.slugger.Slug(string(value)))
+}
+
+func (ids *markdownHeadingIDs) Put(value []byte) {
+	ids.slugger.seen[string(value)] = 0
+}
+
+// Export a directory of Markdown notes to a static HTML site, returns the number of pages and assets written
+func MarkdownExportSite(root string, options MarkdownExportOptions) (int, int, error) {
+	if options.Output == "" {
+		return 0, 0, fmt.Errorf("output directory is required")
+	}
+
+	// Never export the output directory into itself
+	exclude := options.Exclude
+	if output, err := filepath.Abs(options.Output); err == nil {
+		if absRoot, err := filepath.Abs(root); err == nil && strings.HasPrefix(output, absRoot+string(os.PathSeparator)) {
+			rel, _ := filepath.Rel(absRoot, output)
+			exclude = append(exclude, filepath.Join(root, rel))
+		}
+	}
+
+	layout, err := template.New("page").Parse(MarkdownExportTemplate)
+	if options.Template != "" {
+		layout, err = template.ParseFiles(options.Template)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	vault, err := NewMarkdownVault(root, exclude)
+	if err != nil {
+		return 0, 0, err
+	}
+	pages := markdownExportPages(root, vault)
+
+	renderer := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+	)
+
+	var written, copied int
+	for _, note := range vault.Notes {
+		page := pages[note]
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(note)))
+		if err != nil {
+			return written, copied, err
+		}
+
+		source := markdownExportLinks(string(content), note, vault, pages)
+		_, body := MarkdownFrontMatter(source)
+
+		ids := &markdownHeadingIDs{slugger: NewMarkdownSlugger()}
+		document := renderer.Parser().Parse(text.NewReader([]byte(body)), parser.WithContext(parser.NewContext(parser.WithIDs(ids))))
+		var rendered bytes.Buffer
+		if err := renderer.Renderer().Render(&rendered, []byte(body), document); err != nil {
+			return written, copied, err
+		}
+
+		data := MarkdownPageData{
+			Title:   page.Title,
+			Path:    page.Output,
+			Root:    strings.Repeat("../", strings.Count(page.Output, "/")),
+			Content: template.HTML(rendered.String()),
+			Toc:     template.HTML(markdownExportToc(document, []byte(body), options.MinDepth, options.MaxDepth)),
+			Nav:     template.HTML(markdownExportNav(pages, page)),
+		}
+
+		destination := filepath.Join(options.Output, filepath.FromSlash(page.Output))
+		if err := os.MkdirAll(filepath.Dir(destination), 0755); err != nil {
+			return written, copied, err
+		}
+		file, err := os.Create(destination)
+		if err != nil {
+			return written, copied, err
+		}
+		err = layout.Execute(file, data)
+		file.Close()
+		if err != nil {
+			return written, copied, err
+		}
+		written++
+	}
+
+	// Copy attachments with the same layout so relative links keep working
+	for _, file := range vault.Files {
+		if IsMarkdownFile(file) {
+			continue
+		}
+		destination := filepath.Join(options.Output, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(destination), 0755); err != nil {
+			return written, copied, err
+		}
+		if err := mirrorFile(filepath.Join(root, filepath.FromSlash(file)), destination); err != nil {
+			return written, copied, err
+		}
+		copied++
+	}
+
+	return written, copied, nil
+}
+
+// Map notes to their HTML pages, index.md and README.md become index.html
+func markdownExportPages(root string, vault *MarkdownVault) map[string]*markdownPage {
+	hasIndex := make(map[string]bool)
+	for _, note := range vault.Notes {
+		if strings.EqualFold(strings.TrimSuffix(path.Base(note), path.Ext(note)), "index") {
+			hasIndex[path.Dir(note)] = true
+		}
+	}
+
+	pages := make(map[string]*markdownPage)
+	for _, note := range vault.Notes {
+		name := strings.TrimSuffix(path.Base(note), path.Ext(note))
+		output := strings.TrimSuffix(note, path.Ext(note)) + ".html"
+		if strings.EqualFold(name, "index") || (strings.EqualFold(name, "readme") && !hasIndex[path.Dir(note)]) {
+			output = path.Join(path.Dir(note), "index.html")
+		}
+
+		// Title from the first level 1 heading, or the file name
+		title := name
+		content, _ := os.ReadFile(filepath.Join(root, filepath.FromSlash(note)))
+		for _, heading := range ParseMarkdownHeadings(string(content)) {
+			if heading.Level == 1 {
+				title = MarkdownPlainText(heading.Text)
+				break
+			}
+		}
+		pages[note] = &markdownPage{Source: note, Output: output, Title: title}
+	}
+	return pages
+}
+
+// Relative URL from one page to a site path, path segments are escaped
+func markdownExportHref(from string, to string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(from)), filepath.FromSlash(to))
+	if err != nil {
+		rel = to
+	}
+	return (&url.URL{Path: filepath.ToSlash(rel)}).String()
+}
+
+// Resolve wikilinks and relative Markdown links of a note to the exported pages
+func markdownExportLinks(content string, note string, vault *MarkdownVault, pages map[string]*markdownPage) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	links := ParseMarkdownLinks(content)
+	if len(links) == 0 {
+		return content
+	}
+
+	byLine := make(map[int][]MarkdownLink)
+	for _, link := range links {
+		byLine[link.Line] = append(byLine[link.Line], link)
+	}
+	from := pages[note].Output
+
+	// Page URL of a resolved vault path, with an optional heading anchor
+	pageHref := func(resolved string, anchor string) (string, bool) {
+		target := resolved
+		if page, ok := pages[resolved]; ok {
+			target = page.Output
+		} else if vault.exists[resolved] && !SliceContainsString(vault.Files, resolved) {
+			// Directory, link its index page when there is one
+			index := ""
+			for _, page := range pages {
+				if path.Dir(page.Output) == resolved && path.Base(page.Output) == "index.html" {
+					index = page.Output
+				}
+			}
+			if index == "" {
+				return "", false
+			}
+			target = index
+		} else if IsMarkdownFile(resolved) {
+			return "", false
+		}
+		href := markdownExportHref(from, target)
+		if anchor != "" {
+			href += "#" + MarkdownAnchor(anchor)
+		}
+		return href, true
+	}
+
+	lines := strings.Split(content, "\n")
+	for number, lineLinks := range byLine {
+		line := lines[number-1]
+		type edit struct {
+			start, end int
+			value      string
+		}
+		var edits []edit
+
+		for _, link := range lineLinks {
+			switch link.Kind {
+			case LinkWikilink, LinkEmbed:
+				target, anchor := link.Target, ""
+				if hash := strings.Index(target, "#"); hash >= 0 {
+					target, anchor = target[:hash], target[hash+1:]
+				}
+				resolved, ok := note, true
+				if target != "" {
+					resolved, ok = vault.ResolveWikilink(target)
+				}
+				var href string
+				if ok {
+					href, ok = pageHref(resolved, anchor)
+				}
+
+				label := strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(link.Text)
+				var value string
+				switch {
+				case !ok:
+					value = `<span class="broken-link">` + html.EscapeString(link.Text) + `</span>`
+				case link.Kind == LinkEmbed && !IsMarkdownFile(resolved):
+					value = "![" + label + "](" + href + ")"
+				default:
+					value = "[" + label + "](" + href + ")"
+				}
+				edits = append(edits, edit{link.Start, link.End, value})
+
+			case LinkInline, LinkImage, LinkDefinition, LinkHTML:
+				if link.TargetEnd <= link.TargetStart || IsExternalLink(link.Target) || strings.HasPrefix(link.Target, "#") {
+					continue
+				}
+				target, anchor := link.Target, ""
+				if hash := strings.Index(target, "#"); hash >= 0 {
+					target, anchor = target[:hash], target[hash+1:]
+				}
+				if decoded, err := url.PathUnescape(target); err == nil {
+					target = decoded
+				}
+				if decoded, err := url.PathUnescape(anchor); err == nil {
+					anchor = decoded
+				}
+				resolved, ok := vault.ResolveRelative(note, target)
+				if !ok {
+					continue
+				}
+				if _, isPage := pages[resolved]; !isPage && SliceContainsString(vault.Files, resolved) {
+					continue
+				}
+				if href, ok := pageHref(resolved, anchor); ok {
+					edits = append(edits, edit{link.TargetStart, link.TargetEnd, href})
+				}
+			}
+		}
+
+		// Apply from the end so earlier offsets stay valid, nested links never overlap target spans
+		sort.Slice(edits, func(i, j int) bool {
+			return edits[i].start > edits[j].start
+		})
+		for _, e := range edits {
+			line = line[:e.start] + e.value + line[e.end:]
+		}
+		lines[number-1] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Render the table of contents of a page from its parsed headings
+func markdownExportToc(document ast.Node, source []byte, minDepth int, maxDepth int) string {
+	if minDepth <= 0 {
+		minDepth = 1
+	}
+	if maxDepth <= 0 || maxDepth > 6 {
+		maxDepth = 6
+	}
+
+	var toc strings.Builder
+	title := false
+	ast.Walk(document, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := node.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		// The first level 1 heading is the page title
+		if heading.Level == 1 && !title {
+			title = true
+			return ast.WalkSkipChildren, nil
+		}
+		if heading.Level < minDepth || heading.Level > maxDepth {
+			return ast.WalkSkipChildren, nil
+		}
+		id, _ := heading.AttributeString("id")
+		anchor, _ := id.([]byte)
+		fmt.Fprintf(&toc, "<li class=\"toc-h%d\"><a href=\"#%s\">%s</a></li>\n", heading.Level, html.EscapeString(string(anchor)), html.EscapeString(string(heading.Text(source))))
+		return ast.WalkSkipChildren, nil
+	})
+
+	if toc.Len() == 0 {
+		return ""
+	}
+	return "<ul>\n" + toc.String() + "</ul>"
+}
+
+// Render the navigation sidebar of a page from the directory tree
+func markdownExportNav(pages map[string]*markdownPage, current *markdownPage) string {
+	var sources []string
+	for source := range pages {
+		sources = append(sources, source)
+	}
+
+	// Directories first, then files, alphabetically
+	sort.Slice(sources, func(i, j int) bool {
+		a, b := strings.Split(sources[i], "/"), strings.Split(sources[j], "/")
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] == b[k] {
+				continue
+			}
+			aDir, bDir := k < len(a)-1, k < len(b)-1
+			if aDir != bDir {
+				return aDir
+			}
+			return strings.ToLower(a[k]) < strings.ToLower(b[k])
+		}
+		return len(a) < len(b)
+	})
+
+	var nav strings.Builder
+	nav.WriteString("<ul>\n")
+	var open []string
+	for _, source := range sources {
+		page := pages[source]
+		dirs := strings.Split(source, "/")
+		dirs = dirs[:len(dirs)-1]
+
+		// Close directories that are not shared, open the new ones
+		shared := 0
+		for shared < len(open) && shared < len(dirs) && open[shared] == dirs[shared] {
+			shared++
+		}
+		for len(open) > shared {
+			nav.WriteString("</ul></li>\n")
+			open = open[:len(open)-1]
+		}
+		for _, dir := range dirs[shared:] {
+			fmt.Fprintf(&nav, "<li><span class=\"dir\">%s</span><ul>\n", html.EscapeString(dir))
+			open = append(open, dir)
+		}
+
+		class := ""
+		if page == current {
+			class = ` class="active"`
+		}
+		fmt.Fprintf(&nav, "<li><a href=\"%s\"%s>%s</a></li>\n", html.EscapeString(markdownExportHref(current.Output, page.Output)), class, html.EscapeString(page.Title))
+	}
+	for range open {
+		nav.WriteString("</ul></li>\n")
+	}
+	nav.WriteString("</ul>")
+	return nav.String()
+}
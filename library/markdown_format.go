@@ -0,0 +1,791 @@
+package library
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Markdown rules, formatting rules are fixed by the formatter, the others are only reported
+const (
+	RuleHeadingStyle       = "heading-style"
+	RuleListMarker         = "list-marker"
+	RuleListIndent         = "list-indent"
+	RuleEmphasisStyle      = "emphasis-style"
+	RuleTableAlignment     = "table-alignment"
+	RuleBlankLines         = "blank-lines"
+	RuleTrailingWhitespace = "trailing-whitespace"
+	RuleFinalNewline       = "final-newline"
+	RuleHeadingIncrement   = "heading-increment"
+	RuleDuplicateHeading   = "duplicate-heading"
+	RuleBareURL            = "bare-url"
+	RuleLineLength         = "line-length"
+)
+
+// Markdown Lint Config, read from markdown.json in the current directory or --config
+type MarkdownLintConfig struct {
+	Rules      map[string]bool `json:"rules"`       // Enable or disable rules, all rules are enabled by default
+	LineLength int             `json:"line_length"` // Maximum line length
+	ListMarker string          `json:"list_marker"` // Unordered list marker: - * +
+	ListIndent int             `json:"list_indent"` // Minimum spaces per nested list level, at least the parent content column
+	Emphasis   string          `json:"emphasis"`    // Emphasis marker: * or _
+}
+
+// Markdown Lint Issue
+type MarkdownLintIssue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Default Markdown Lint Config
+func DefaultMarkdownLintConfig() MarkdownLintConfig {
+	return MarkdownLintConfig{Rules: map[string]bool{}, LineLength: 120, ListMarker: "-", ListIndent: 2, Emphasis: "*"}
+}
+
+// Read the lint config, missing fields keep their defaults. A missing default config file is not an error.
+func ReadMarkdownLintConfig(path string) (MarkdownLintConfig, error) {
+	config := DefaultMarkdownLintConfig()
+	explicit := path != ""
+	if !explicit {
+		path = "markdown.json"
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && os.IsNotExist(err) {
+			return config, nil
+		}
+		return config, err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	if config.Rules == nil {
+		config.Rules = map[string]bool{}
+	}
+	if config.ListMarker != "-" && config.ListMarker != "*" && config.ListMarker != "+" {
+		return config, fmt.Errorf("invalid list_marker: %q (- * +)", config.ListMarker)
+	}
+	if config.Emphasis != "*" && config.Emphasis != "_" {
+		return config, fmt.Errorf("invalid emphasis: %q (* _)", config.Emphasis)
+	}
+	if config.ListIndent <= 0 {
+		config.ListIndent = 2
+	}
+	return config, nil
+}
+
+// Check if a rule is enabled
+func (c MarkdownLintConfig) Enabled(rule string) bool {
+	enabled, configured := c.Rules[rule]
+	return !configured || enabled
+}
+
+// Block patterns
+var (
+	markdownThematicPattern  = regexp.MustCompile(`^ {0,3}([-*_])(?:[ \t]*[-*_]){2,}[ \t]*$`)
+	markdownBulletPattern    = regexp.MustCompile(`^([ \t]*)([-*+])([ \t]+)(.*)$`)
+	markdownOrderedPattern   = regexp.MustCompile(`^([ \t]*)(\d{1,9})([.)])([ \t]+)(.*)$`)
+	markdownSetextPattern    = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	markdownTableRowPattern  = regexp.MustCompile(`^\s*\|.*\|\s*$|^[^|]+\|`)
+	markdownTableRulePattern = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	markdownBareURLPattern   = regexp.MustCompile(`https?://[^\s<>()\[\]]+`)
+)
+
+// HTML block start patterns, CommonMark types 1, 6 and 7. Types 2 to 5 start with a fixed prefix.
+var (
+	markdownHTMLRawPattern   = regexp.MustCompile(`(?i)^ {0,3}<(script|pre|style|textarea)(?:\s|>|$)`)
+	markdownHTMLBlockPattern = regexp.MustCompile(`(?i)^ {0,3}</?(?:address|article|aside|base|basefont|blockquote|body|caption|center|col|colgroup|dd|details|dialog|dir|div|dl|dt|fieldset|figcaption|figure|footer|form|frame|frameset|h[1-6]|head|header|hr|html|iframe|legend|li|link|main|menu|menuitem|nav|noframes|ol|optgroup|option|p|param|search|section|summary|table|tbody|td|tfoot|th|thead|title|tr|track|ul)(?:\s|/?>|$)`)
+	markdownHTMLTagPattern7  = regexp.MustCompile(`^ {0,3}(?:<[A-Za-z][A-Za-z0-9-]*(?:\s+[A-Za-z_:][\w.:-]*(?:\s*=\s*(?:[^\s"'=<>` + "`" + `]+|'[^']*'|"[^"]*"))?)*\s*/?>|</[A-Za-z][A-Za-z0-9-]*\s*>)\s*$`)
+)
+
+// Emphasis patterns, intraword underscores and bold markers are left alone
+var markdownEmphasisPatterns = map[string][2]*regexp.Regexp{
+	"*": {
+		regexp.MustCompile(`(^|[^\w_\\])__(\S(?:.*?\S)?)__($|[^\w_])`),
+		regexp.MustCompile(`(^|[^\w_\\])_(\S(?:[^_]*?\S)?)_($|[^\w_])`),
+	},
+	"_": {
+		regexp.MustCompile(`(^|[^\w*\\])\*\*(\S(?:.*?\S)?)\*\*($|[^\w*])`),
+		regexp.MustCompile(`(^|[^\w*\\])\*(\S(?:[^*]*?\S)?)\*($|[^\w*])`),
+	},
+}
+
+// Markdown formatter state for a single document
+type markdownFormatter struct {
+	config MarkdownLintConfig
+	issues []MarkdownLintIssue
+}
+
+// Record an issue for an enabled rule, returns false when the rule is disabled
+func (f *markdownFormatter) report(rule string, line int, message string) bool {
+	if !f.config.Enabled(rule) {
+		return false
+	}
+	f.issues = append(f.issues, MarkdownLintIssue{Line: line, Rule: rule, Message: message})
+	return true
+}
+
+// Markdown line with its number in the original document
+type markdownLine struct {
+	text   string
+	number int
+	code   bool // Code block, front matter, HTML block or math block, never changed
+	kind   string
+}
+
+// Format a Markdown document, returns the formatted content and the formatting issues that were fixed
+func MarkdownFormat(content string, config MarkdownLintConfig) (string, []MarkdownLintIssue) {
+	formatter := &markdownFormatter{config: config}
+	crlf := DetectLineEndings([]byte(content)) == "crlf"
+	source := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	hadFinalNewline := len(source) > 0 && source[len(source)-1] == ""
+	if hadFinalNewline {
+		source = source[:len(source)-1]
+	}
+	code := MarkdownCodeLines(source)
+	opaque := markdownOpaqueLines(source, code)
+
+	lines := make([]markdownLine, 0, len(source))
+	for i, text := range source {
+		lines = append(lines, markdownLine{text: text, number: i + 1, code: code[i] || opaque[i] != "", kind: opaque[i]})
+	}
+
+	lines = formatter.indentedCode(lines)
+	lines = formatter.inline(lines)
+	lines = formatter.headings(lines)
+	lines = formatter.lists(lines)
+	lines = formatter.tables(lines)
+	lines = formatter.blankLines(lines)
+
+	var output []string
+	for _, line := range lines {
+		output = append(output, line.text)
+	}
+	result := strings.Join(output, "\n")
+	if result != "" && (hadFinalNewline || formatter.report(RuleFinalNewline, len(source), "missing final newline")) {
+		result += "\n"
+	}
+	if crlf {
+		result = strings.ReplaceAll(result, "\n", "\r\n")
+	}
+	return result, formatter.issues
+}
+
+// Kind of the lines of raw HTML blocks ("html") and $$ math blocks ("math"), which are kept as is like code
+func markdownOpaqueLines(lines []string, code []bool) []string {
+	kinds := make([]string, len(lines))
+	for i := 0; i < len(lines); i++ {
+		if code[i] {
+			continue
+		}
+		text := lines[i]
+		trimmed := strings.TrimSpace(text)
+		indented := len(text)-len(strings.TrimLeft(text, " ")) > 3 || strings.HasPrefix(text, "\t")
+
+		// The block ends at the line holding the end marker, or before a blank line when the marker is empty
+		kind, end, rest := "html", "", ""
+		switch {
+		case strings.HasPrefix(trimmed, "$$"):
+			kind, end, rest = "math", "$$", trimmed[2:]
+		case indented:
+			continue
+		case markdownHTMLRawPattern.MatchString(text):
+			name := strings.ToLower(markdownHTMLRawPattern.FindStringSubmatch(text)[1])
+			end, rest = "</"+name+">", strings.ToLower(trimmed)
+		case strings.HasPrefix(trimmed, "<!--"):
+			end, rest = "-->", trimmed[4:]
+		case strings.HasPrefix(trimmed, "<?"):
+			end, rest = "?>", trimmed[2:]
+		case strings.HasPrefix(trimmed, "<![CDATA["):
+			end, rest = "]]>", trimmed[9:]
+		case strings.HasPrefix(trimmed, "<!") && len(trimmed) > 2 && (trimmed[2] >= 'A' && trimmed[2] <= 'Z' || trimmed[2] >= 'a' && trimmed[2] <= 'z'):
+			end, rest = ">", trimmed[2:]
+		case markdownHTMLBlockPattern.MatchString(text):
+		case markdownHTMLTagPattern7.MatchString(text) && (i == 0 || strings.TrimSpace(lines[i-1]) == "" || code[i-1] || kinds[i-1] != ""):
+			// A lone tag can't interrupt a paragraph
+		default:
+			continue
+		}
+
+		j := i
+		if end == "" {
+			for j+1 < len(lines) && strings.TrimSpace(lines[j+1]) != "" {
+				j++
+			}
+		} else if !strings.Contains(rest, end) {
+			for j+1 < len(lines) {
+				j++
+				line := lines[j]
+				if kind == "html" {
+					line = strings.ToLower(line)
+				}
+				if strings.Contains(line, end) {
+					break
+				}
+			}
+		}
+		for k := i; k <= j; k++ {
+			kinds[k] = kind
+		}
+		i = j
+	}
+	return kinds
+}
+
+// Mark indented code blocks, an indented line after a blank line outside of lists
+func (f *markdownFormatter) indentedCode(lines []markdownLine) []markdownLine {
+	inList := false
+	for i := range lines {
+		if lines[i].code {
+			continue
+		}
+		text := lines[i].text
+		blankBefore := i == 0 || strings.TrimSpace(lines[i-1].text) == "" || markdownHeadingPattern.MatchString(lines[i-1].text)
+		indented := strings.HasPrefix(text, "    ") || strings.HasPrefix(text, "\t")
+		switch {
+		case strings.TrimSpace(text) == "":
+		case markdownBulletPattern.MatchString(text) || markdownOrderedPattern.MatchString(text):
+			inList = true
+		case indented && !inList && (blankBefore || (i > 0 && lines[i-1].kind == "indented")):
+			lines[i].code = true
+			lines[i].kind = "indented"
+		case !indented:
+			inList = false
+		}
+	}
+	return lines
+}
+
+// Trailing whitespace and emphasis markers
+func (f *markdownFormatter) inline(lines []markdownLine) []markdownLine {
+	for i := range lines {
+		if lines[i].code {
+			continue
+		}
+		text := lines[i].text
+
+		// Trailing whitespace, a hard line break of exactly two spaces is kept
+		trimmed := strings.TrimRight(text, " \t")
+		hardBreak := strings.HasSuffix(text, "  ") && !strings.HasSuffix(text, "   ") && strings.TrimSpace(trimmed) != "" && len(text)-len(trimmed) == 2
+		if trimmed != text && !hardBreak && f.report(RuleTrailingWhitespace, lines[i].number, "trailing whitespace") {
+			text = trimmed
+		}
+
+		// Emphasis markers, outside code spans, link targets and URLs
+		if patterns, ok := markdownEmphasisPatterns[f.config.Emphasis]; ok {
+			for k, pattern := range patterns {
+				marker := strings.Repeat(f.config.Emphasis, 2-k)
+				masked := markdownMaskInline(text)
+				matches := pattern.FindAllStringSubmatchIndex(masked, -1)
+				if len(matches) == 0 {
+					continue
+				}
+				if !f.report(RuleEmphasisStyle, lines[i].number, "emphasis should use "+f.config.Emphasis) {
+					break
+				}
+				for m := len(matches) - 1; m >= 0; m-- {
+					match := matches[m]
+					text = text[:match[3]] + marker + text[match[4]:match[5]] + marker + text[match[6]:]
+				}
+			}
+		}
+		lines[i].text = text
+	}
+	return lines
+}
+
+// Mask code spans, link targets, autolinks, HTML and bare URLs of a line, keeping byte offsets
+func markdownMaskInline(line string) string {
+	masked := []byte(maskCodeSpans(line))
+	for _, link := range parseMarkdownLineLinks(string(masked), 1, map[string]string{}) {
+		for i := link.TargetStart; i < link.TargetEnd && i < len(masked); i++ {
+			masked[i] = ' '
+		}
+		if link.Kind == LinkAutolink || link.Kind == LinkWikilink || link.Kind == LinkEmbed {
+			for i := link.Start; i < link.End; i++ {
+				masked[i] = ' '
+			}
+		}
+	}
+	for _, pattern := range []*regexp.Regexp{markdownHTMLTagPattern, markdownBareURLPattern} {
+		for _, match := range pattern.FindAllIndex(masked, -1) {
+			for i := match[0]; i < match[1]; i++ {
+				masked[i] = ' '
+			}
+		}
+	}
+	return string(masked)
+}
+
+// ATX headings without closing hashes and with a single space, setext headings become ATX
+func (f *markdownFormatter) headings(lines []markdownLine) []markdownLine {
+	var output []markdownLine
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if line.code {
+			output = append(output, line)
+			continue
+		}
+
+		// Setext heading: paragraph line followed by === or ---
+		if i+1 < len(lines) && !lines[i+1].code && strings.TrimSpace(line.text) != "" && markdownSetextPattern.MatchString(lines[i+1].text) &&
+			markdownParagraphLine(line.text) && (i == 0 || strings.TrimSpace(lines[i-1].text) == "" || lines[i-1].kind == "heading") {
+			level := 1
+			if strings.Contains(lines[i+1].text, "-") {
+				level = 2
+			}
+			if f.report(RuleHeadingStyle, line.number, "setext heading should use ATX style") {
+				line.text = strings.Repeat("#", level) + " " + strings.TrimSpace(line.text)
+				line.kind = "heading"
+				output = append(output, line)
+				i++
+				continue
+			}
+		}
+
+		if match := markdownHeadingPattern.FindStringSubmatch(line.text); match != nil {
+			line.kind = "heading"
+			formatted := strings.TrimSpace(match[1] + " " + strings.TrimSpace(match[2]))
+			if formatted != line.text && f.report(RuleHeadingStyle, line.number, "heading should be \""+formatted+"\"") {
+				line.text = formatted
+			}
+		}
+		output = append(output, line)
+	}
+	return output
+}
+
+// Check if a line can be part of a paragraph
+func markdownParagraphLine(text string) bool {
+	return !markdownHeadingPattern.MatchString(text) && !markdownBulletPattern.MatchString(text) && !markdownOrderedPattern.MatchString(text) &&
+		!markdownThematicPattern.MatchString(text) && !strings.HasPrefix(strings.TrimSpace(text), ">") && !strings.HasPrefix(strings.TrimSpace(text), "|") &&
+		!strings.HasPrefix(strings.TrimSpace(text), "<")
+}
+
+// Unordered list markers and nested list indentation. Nested items start at least at the content
+// column of their parent item, so items under an ordered "1. " are indented by 3 spaces.
+func (f *markdownFormatter) lists(lines []markdownLine) []markdownLine {
+	type listLevel struct {
+		indent  int // Original indentation
+		wanted  int // Formatted indentation
+		content int // Formatted content column
+	}
+	var stack []listLevel // Open list levels
+	delta := 0            // Indentation change of the last item, applied to its continuation lines
+	for i := range lines {
+		line := &lines[i]
+		if line.code || line.kind == "heading" {
+			if line.kind == "heading" {
+				stack, delta = nil, 0
+			}
+			continue
+		}
+		text := line.text
+		if strings.TrimSpace(text) == "" || markdownThematicPattern.MatchString(text) {
+			continue
+		}
+
+		bullet := markdownBulletPattern.FindStringSubmatch(text)
+		ordered := markdownOrderedPattern.FindStringSubmatch(text)
+		if bullet == nil && ordered == nil {
+			indent := markdownIndentWidth(text)
+			if indent == 0 {
+				stack, delta = nil, 0
+			} else if delta != 0 && indent+delta >= 0 && f.config.Enabled(RuleListIndent) {
+				line.text = strings.Repeat(" ", indent+delta) + strings.TrimLeft(text, " \t")
+			}
+			continue
+		}
+		line.kind = "list"
+
+		var indent int
+		if bullet != nil {
+			indent = markdownIndentWidth(bullet[1])
+		} else {
+			indent = markdownIndentWidth(ordered[1])
+		}
+		for len(stack) > 0 && stack[len(stack)-1].indent > indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) > 0 && stack[len(stack)-1].indent == indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		wanted := indent
+		if f.config.Enabled(RuleListIndent) {
+			wanted = 0
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				wanted = parent.wanted + f.config.ListIndent
+				if wanted < parent.content {
+					wanted = parent.content
+				}
+			}
+			if wanted != indent {
+				f.report(RuleListIndent, line.number, fmt.Sprintf("list item indentation should be %d spaces", wanted))
+			}
+		}
+		delta = wanted - indent
+
+		var marker, rest string
+		if bullet != nil {
+			marker, rest = bullet[2], bullet[4]
+			if marker != f.config.ListMarker && f.report(RuleListMarker, line.number, "list marker should be "+f.config.ListMarker) {
+				marker = f.config.ListMarker
+			}
+		} else {
+			marker, rest = ordered[2]+ordered[3], ordered[5]
+		}
+		line.text = strings.Repeat(" ", wanted) + marker + " " + rest
+		stack = append(stack, listLevel{indent: indent, wanted: wanted, content: wanted + len(marker) + 1})
+	}
+	return lines
+}
+
+// Width of leading whitespace, tabs count as 4 spaces
+func markdownIndentWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// Align table columns
+func (f *markdownFormatter) tables(lines []markdownLine) []markdownLine {
+	for i := 0; i+1 < len(lines); i++ {
+		if lines[i].code || lines[i+1].code || !markdownTableRowPattern.MatchString(lines[i].text) || !markdownTableRulePattern.MatchString(lines[i+1].text) || !strings.Contains(lines[i+1].text, "-") {
+			continue
+		}
+		end := i + 2
+		for end < len(lines) && !lines[end].code && strings.TrimSpace(lines[end].text) != "" && strings.Contains(lines[end].text, "|") {
+			end++
+		}
+
+		var rows [][]string
+		for j := i; j < end; j++ {
+			rows = append(rows, splitMarkdownTableRow(lines[j].text))
+		}
+		formatted := formatMarkdownTable(rows)
+		changed := false
+		for j := i; j < end; j++ {
+			if lines[j].text != formatted[j-i] {
+				changed = true
+			}
+		}
+		if changed && f.report(RuleTableAlignment, lines[i].number, "table columns are not aligned") {
+			for j := i; j < end; j++ {
+				lines[j].text = formatted[j-i]
+			}
+		}
+		for j := i; j < end; j++ {
+			lines[j].kind = "table"
+		}
+		i = end - 1
+	}
+	return lines
+}
+
+// Split a table row on unescaped pipes outside code spans
+func splitMarkdownTableRow(row string) []string {
+	masked := maskCodeSpans(row)
+	row = strings.TrimSpace(row)
+	masked = strings.TrimSpace(masked)
+	if strings.HasPrefix(masked, "|") {
+		row, masked = row[1:], masked[1:]
+	}
+	if strings.HasSuffix(masked, "|") && !strings.HasSuffix(masked, `\|`) {
+		row, masked = row[:len(row)-1], masked[:len(masked)-1]
+	}
+
+	var cells []string
+	start := 0
+	for i := 0; i < len(masked); i++ {
+		if masked[i] == '|' && (i == 0 || masked[i-1] != '\\') {
+			cells = append(cells, strings.TrimSpace(row[start:i]))
+			start = i + 1
+		}
+	}
+	return append(cells, strings.TrimSpace(row[start:]))
+}
+
+// Render table rows with padded cells, the second row holds the alignment
+func formatMarkdownTable(rows [][]string) []string {
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	align := make([]string, columns)
+	widths := make([]int, columns)
+	for c := 0; c < columns; c++ {
+		widths[c] = 3
+		if c < len(rows[1]) {
+			rule := rows[1][c]
+			switch {
+			case strings.HasPrefix(rule, ":") && strings.HasSuffix(rule, ":"):
+				align[c] = "center"
+			case strings.HasSuffix(rule, ":"):
+				align[c] = "right"
+			case strings.HasPrefix(rule, ":"):
+				align[c] = "left"
+			}
+		}
+	}
+	for r, row := range rows {
+		if r == 1 {
+			continue
+		}
+		for c, cell := range row {
+			if width := utf8.RuneCountInString(cell); width > widths[c] {
+				widths[c] = width
+			}
+		}
+	}
+
+	var output []string
+	for r, row := range rows {
+		cells := make([]string, columns)
+		for c := 0; c < columns; c++ {
+			cell := ""
+			if c < len(row) {
+				cell = row[c]
+			}
+			padding := widths[c] - utf8.RuneCountInString(cell)
+			switch {
+			case r == 1:
+				dashes := widths[c]
+				left, right := "", ""
+				if align[c] == "left" || align[c] == "center" {
+					left, dashes = ":", dashes-1
+				}
+				if align[c] == "right" || align[c] == "center" {
+					right, dashes = ":", dashes-1
+				}
+				cell = left + strings.Repeat("-", dashes) + right
+			case align[c] == "right":
+				cell = strings.Repeat(" ", padding) + cell
+			case align[c] == "center":
+				cell = strings.Repeat(" ", padding/2) + cell + strings.Repeat(" ", padding-padding/2)
+			default:
+				cell += strings.Repeat(" ", padding)
+			}
+			cells[c] = cell
+		}
+		output = append(output, "| "+strings.Join(cells, " | ")+" |")
+	}
+	return output
+}
+
+// Blank lines around headings, code blocks, lists and tables, no repeated blank lines
+func (f *markdownFormatter) blankLines(lines []markdownLine) []markdownLine {
+	// Front matter and the line after it are kept as is
+	source := make([]string, len(lines))
+	for i, line := range lines {
+		source[i] = line.text
+	}
+	frontMatter := markdownFrontMatterLines(source)
+
+	// Block kind used to decide separation: heading, fence, list, table or text
+	block := func(line markdownLine) string {
+		switch {
+		case line.code && line.kind == "":
+			return "fence"
+		case line.kind != "":
+			return line.kind
+		default:
+			return "text"
+		}
+	}
+
+	var output []markdownLine
+	for i, line := range lines {
+		blank := strings.TrimSpace(line.text) == "" && !line.code
+		if i < frontMatter {
+			output = append(output, line)
+			continue
+		}
+
+		if blank {
+			// Leading and repeated blank lines
+			if len(output) == 0 || strings.TrimSpace(output[len(output)-1].text) == "" && !output[len(output)-1].code {
+				if f.report(RuleBlankLines, line.number, "multiple blank lines") {
+					continue
+				}
+			}
+			output = append(output, line)
+			continue
+		}
+
+		if len(output) > frontMatter {
+			previous := output[len(output)-1]
+			if strings.TrimSpace(previous.text) != "" || previous.code {
+				before, current := block(previous), block(line)
+				fenceStart := current == "fence" && (i == 0 || !lines[i-1].code || lines[i-1].kind == "indented")
+				fenceEnd := before == "fence" && !line.code
+				needed := fenceStart || fenceEnd || current == "heading" || before == "heading" ||
+					(current != before && (current == "list" || current == "table") && !(before == "list" && markdownIndentWidth(line.text) > 0)) ||
+					(before == "table" && current != "table")
+				if line.code && !fenceStart {
+					needed = false
+				}
+				if needed && f.report(RuleBlankLines, line.number, "missing blank line around "+current) {
+					output = append(output, markdownLine{number: line.number})
+				}
+			}
+		}
+		output = append(output, line)
+	}
+
+	// Trailing blank lines
+	for len(output) > frontMatter && strings.TrimSpace(output[len(output)-1].text) == "" && !output[len(output)-1].code {
+		if !f.report(RuleBlankLines, output[len(output)-1].number, "trailing blank lines") {
+			break
+		}
+		output = output[:len(output)-1]
+	}
+	return output
+}
+
+// Lint a Markdown document: formatting issues and content rules
+func MarkdownLint(content string, config MarkdownLintConfig) []MarkdownLintIssue {
+	_, issues := MarkdownFormat(content, config)
+	report := func(rule string, line int, message string) {
+		if config.Enabled(rule) {
+			issues = append(issues, MarkdownLintIssue{Line: line, Rule: rule, Message: message})
+		}
+	}
+
+	// Heading levels and duplicates
+	previous := 0
+	seen := make(map[string]int)
+	for _, heading := range ParseMarkdownHeadings(content) {
+		if previous > 0 && heading.Level > previous+1 {
+			report(RuleHeadingIncrement, heading.Line, fmt.Sprintf("heading level skipped from %d to %d", previous, heading.Level))
+		}
+		previous = heading.Level
+		key := strings.ToLower(MarkdownPlainText(heading.Text))
+		if first, exists := seen[key]; exists {
+			report(RuleDuplicateHeading, heading.Line, fmt.Sprintf("duplicate heading %q (line %d)", heading.Text, first))
+		} else {
+			seen[key] = heading.Line
+		}
+	}
+
+	// Bare URLs and long lines outside code
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	code := MarkdownCodeLines(lines)
+	for i, line := range lines {
+		if code[i] || strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		masked := []byte(maskCodeSpans(line))
+		for _, link := range parseMarkdownLineLinks(string(masked), i+1, map[string]string{}) {
+			for j := link.Start; j < link.End; j++ {
+				masked[j] = ' '
+			}
+		}
+		if markdownDefinitionPattern.Match(masked) {
+			continue
+		}
+		for _, match := range markdownBareURLPattern.FindAllIndex(masked, -1) {
+			report(RuleBareURL, i+1, "bare URL "+line[match[0]:match[1]])
+		}
+
+		// Tables and lines without spaces (long links) are allowed to be long
+		if length := utf8.RuneCountInString(line); config.LineLength > 0 && length > config.LineLength && !strings.HasPrefix(strings.TrimSpace(line), "|") && strings.Contains(strings.TrimSpace(line), " ") {
+			report(RuleLineLength, i+1, fmt.Sprintf("line is %d characters, maximum %d", length, config.LineLength))
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// Format or lint the Markdown files of a path. Returns the issues found, in fix mode files are rewritten
+// unless check or dry run is set.
+func MarkdownFormatPath(path string, exclude []string, config MarkdownLintConfig, lint bool, check bool, dryRun bool) ([]MarkdownLintIssue, error) {
+	root, files, err := collectMarkdownFiles(path, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []MarkdownLintIssue
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		rel, _ := filepath.Rel(root, file)
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			rel = filepath.Base(file)
+		}
+
+		var fileIssues []MarkdownLintIssue
+		if lint {
+			fileIssues = MarkdownLint(string(content), config)
+		} else {
+			var formatted string
+			formatted, fileIssues = MarkdownFormat(string(content), config)
+			if formatted != string(content) {
+				if dryRun {
+					fmt.Print(UnifiedDiff(splitLines(string(content)), splitLines(formatted), "a/"+rel, "b/"+rel, 3))
+				} else if !check {
+					info, err := os.Stat(file)
+					if err != nil {
+						return nil, err
+					}
+					if err := os.WriteFile(file, []byte(formatted), info.Mode().Perm()); err != nil {
+						return nil, err
+					}
+					fmt.Println("✅ Formatted", file)
+				}
+			}
+		}
+		for _, issue := range fileIssues {
+			issue.File = rel
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// Print lint issues
+func PrintMarkdownLintIssues(issues []MarkdownLintIssue, format string) {
+	if format == "json" {
+		if issues == nil {
+			issues = []MarkdownLintIssue{}
+		}
+		PrintJSON(issues)
+		return
+	}
+
+	for _, issue := range issues {
+		fmt.Printf("⚠️ %s:%d: %s (%s)\n", issue.File, issue.Line, issue.Message, issue.Rule)
+	}
+	if len(issues) == 0 {
+		fmt.Println("✅ No issues found")
+	} else {
+		fmt.Println("🐙 There are", len(issues), "issues")
+	}
+}
@@ -0,0 +1,270 @@
+package library
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Markdown Graph of the links between the notes of a vault
+type MarkdownGraph struct {
+	Notes []string       `json:"notes"`
+	Edges []MarkdownEdge `json:"edges"`
+	vault *MarkdownVault
+}
+
+// Markdown Edge is a link from one note to another
+type MarkdownEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Line int    `json:"line"`
+	Kind string `json:"kind"`
+}
+
+// Markdown Graph Node summary
+type MarkdownGraphNode struct {
+	Note      string `json:"note"`
+	Backlinks int    `json:"backlinks"`
+	Links     int    `json:"links"`
+}
+
+// Build the link graph of a directory from wikilinks, embeds and relative Markdown links
+func NewMarkdownGraph(root string, exclude []string) (*MarkdownGraph, error) {
+	vault, err := NewMarkdownVault(root, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	// Links to excluded files or files outside the vault resolve on disk but aren't nodes
+	notes := make(map[string]bool, len(vault.Notes))
+	for _, note := range vault.Notes {
+		notes[note] = true
+	}
+
+	graph := &MarkdownGraph{Notes: vault.Notes, vault: vault}
+	for _, note := range vault.Notes {
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(note)))
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range ParseMarkdownLinks(string(content)) {
+			if target, ok := vault.resolveNote(note, link); ok && target != note && notes[target] {
+				graph.Edges = append(graph.Edges, MarkdownEdge{From: note, To: target, Line: link.Line, Kind: link.Kind})
+			}
+		}
+	}
+	return graph, nil
+}
+
+// Resolve the note a link points to, links to attachments and external links are ignored
+func (v *MarkdownVault) resolveNote(note string, link MarkdownLink) (string, bool) {
+	target := link.Target
+	if hash := strings.Index(target, "#"); hash >= 0 {
+		target = target[:hash]
+	}
+	if target == "" || IsExternalLink(target) {
+		return "", false
+	}
+
+	var resolved string
+	var ok bool
+	switch link.Kind {
+	case LinkWikilink, LinkEmbed:
+		resolved, ok = v.ResolveWikilink(target)
+	case LinkInline, LinkReference, LinkHTML:
+		if decoded, err := url.PathUnescape(target); err == nil {
+			target = decoded
+		}
+		resolved, ok = v.ResolveRelative(note, target)
+	}
+	return resolved, ok && IsMarkdownFile(resolved)
+}
+
+// Find a note by path or wikilink name
+func (g *MarkdownGraph) FindNote(name string) (string, bool) {
+	name = filepath.ToSlash(name)
+	for _, note := range g.Notes {
+		if note == name {
+			return note, true
+		}
+	}
+	return g.vault.ResolveWikilink(strings.TrimSuffix(strings.TrimSuffix(name, "]]"), "[["))
+}
+
+// Links pointing to a note
+func (g *MarkdownGraph) Backlinks(note string) []MarkdownEdge {
+	var backlinks []MarkdownEdge
+	for _, edge := range g.Edges {
+		if edge.To == note {
+			backlinks = append(backlinks, edge)
+		}
+	}
+	return backlinks
+}
+
+// Notes with their number of backlinks and outgoing links, each linked note is counted once
+func (g *MarkdownGraph) Nodes() []MarkdownGraphNode {
+	index := make(map[string]int)
+	nodes := make([]MarkdownGraphNode, len(g.Notes))
+	for i, note := range g.Notes {
+		index[note] = i
+		nodes[i].Note = note
+	}
+	for _, edge := range g.UniqueEdges() {
+		from, fromOk := index[edge.From]
+		to, toOk := index[edge.To]
+		if !fromOk || !toOk {
+			continue
+		}
+		nodes[from].Links++
+		nodes[to].Backlinks++
+	}
+	return nodes
+}
+
+// Notes nothing links to
+func (g *MarkdownGraph) Orphans() []string {
+	var orphans []string
+	for _, node := range g.Nodes() {
+		if node.Backlinks == 0 {
+			orphans = append(orphans, node.Note)
+		}
+	}
+	return orphans
+}
+
+// Notes that link to no other note
+func (g *MarkdownGraph) DeadEnds() []string {
+	var deadEnds []string
+	for _, node := range g.Nodes() {
+		if node.Links == 0 {
+			deadEnds = append(deadEnds, node.Note)
+		}
+	}
+	return deadEnds
+}
+
+// Edges without duplicates between the same notes, sorted
+func (g *MarkdownGraph) UniqueEdges() []MarkdownEdge {
+	seen := make(map[[2]string]bool)
+	var edges []MarkdownEdge
+	for _, edge := range g.Edges {
+		key := [2]string{edge.From, edge.To}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		edges = append(edges, edge)
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
+
+// Export the graph as Graphviz DOT
+func (g *MarkdownGraph) DOT() string {
+	var dot strings.Builder
+	dot.WriteString("digraph notes {\n")
+	dot.WriteString("  node [shape=box];\n")
+	for _, note := range g.Notes {
+		fmt.Fprintf(&dot, "  %s [label=%s];\n", strconv.Quote(note), strconv.Quote(markdownNoteName(note)))
+	}
+	for _, edge := range g.UniqueEdges() {
+		fmt.Fprintf(&dot, "  %s -> %s;\n", strconv.Quote(edge.From), strconv.Quote(edge.To))
+	}
+	dot.WriteString("}\n")
+	return dot.String()
+}
+
+// Export the graph as a Mermaid flowchart
+func (g *MarkdownGraph) Mermaid() string {
+	ids := make(map[string]string)
+	var mermaid strings.Builder
+	mermaid.WriteString("graph LR\n")
+	for i, note := range g.Notes {
+		ids[note] = fmt.Sprintf("n%d", i)
+		label := strings.ReplaceAll(markdownNoteName(note), `"`, "#quot;")
+		fmt.Fprintf(&mermaid, "  %s[\"%s\"]\n", ids[note], label)
+	}
+	for _, edge := range g.UniqueEdges() {
+		fmt.Fprintf(&mermaid, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+	}
+	return mermaid.String()
+}
+
+// Note name without directory and extension
+func markdownNoteName(note string) string {
+	name := filepath.Base(filepath.FromSlash(note))
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// Print the graph as table, dot, mermaid or json
+func PrintMarkdownGraph(graph *MarkdownGraph, format string) {
+	switch format {
+	case "dot":
+		fmt.Print(graph.DOT())
+	case "mermaid":
+		fmt.Print(graph.Mermaid())
+	case "json":
+		output := struct {
+			Notes []string       `json:"notes"`
+			Edges []MarkdownEdge `json:"edges"`
+		}{graph.Notes, graph.UniqueEdges()}
+		if output.Notes == nil {
+			output.Notes = []string{}
+		}
+		if output.Edges == nil {
+			output.Edges = []MarkdownEdge{}
+		}
+		PrintJSON(output)
+	default:
+		var rows [][]string
+		for _, node := range graph.Nodes() {
+			rows = append(rows, []string{node.Note, strconv.Itoa(node.Backlinks), strconv.Itoa(node.Links)})
+		}
+		PrintTable([]string{"NOTE", "BACKLINKS", "LINKS"}, rows)
+		fmt.Println("🐙 There are", len(graph.Notes), "notes and", len(graph.UniqueEdges()), "links")
+	}
+}
+
+// Print backlinks of a note
+func PrintBacklinks(note string, backlinks []MarkdownEdge, format string) {
+	if format == "json" {
+		if backlinks == nil {
+			backlinks = []MarkdownEdge{}
+		}
+		PrintJSON(backlinks)
+		return
+	}
+
+	var rows [][]string
+	for _, edge := range backlinks {
+		rows = append(rows, []string{fmt.Sprintf("%s:%d", edge.From, edge.Line), edge.Kind})
+	}
+	PrintTable([]string{"FILE", "KIND"}, rows)
+	fmt.Println("🐙 There are", len(backlinks), "backlinks to", note)
+}
+
+// Print a list of notes
+func PrintNotes(notes []string, format string, description string) {
+	if format == "json" {
+		if notes == nil {
+			notes = []string{}
+		}
+		PrintJSON(notes)
+		return
+	}
+
+	for _, note := range notes {
+		fmt.Println(note)
+	}
+	fmt.Println("🐙 There are", len(notes), description)
+}
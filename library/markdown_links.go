@@ -0,0 +1,256 @@
+package library
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Markdown Link kinds
+const (
+	LinkInline     = "inline"
+	LinkImage      = "image"
+	LinkReference  = "reference"
+	LinkDefinition = "definition"
+	LinkAutolink   = "autolink"
+	LinkHTML       = "html"
+	LinkWikilink   = "wikilink"
+	LinkEmbed      = "embed"
+)
+
+// Markdown Link found in a document
+type MarkdownLink struct {
+	Kind   string `json:"kind"`
+	Text   string `json:"text"`
+	Target string `json:"target"`
+	Label  string `json:"label,omitempty"` // Reference label for reference links and definitions
+	Line   int    `json:"line"`
+
+	// Byte offsets in the line of the whole link, its text and its target
+	Start       int `json:"-"`
+	End         int `json:"-"`
+	TextStart   int `json:"-"`
+	TextEnd     int `json:"-"`
+	TargetStart int `json:"-"`
+	TargetEnd   int `json:"-"`
+}
+
+// Link patterns
+var (
+	markdownWikilinkPattern   = regexp.MustCompile(`(!?)\[\[([^\[\]]+?)\]\]`)
+	markdownInlinePattern     = regexp.MustCompile(`(!?)\[((?:[^\[\]]|\[[^\[\]]*\])*)\]\(\s*(<[^>]*>|[^\s()]*(?:\([^\s()]*\)[^\s()]*)*)(?:\s+(?:"[^"]*"|'[^']*'|\([^)]*\)))?\s*\)`)
+	markdownReferencePattern  = regexp.MustCompile(`(!?)\[((?:[^\[\]]|\[[^\[\]]*\])*)\]\[([^\[\]]*)\]`)
+	markdownShortcutPattern   = regexp.MustCompile(`(!?)\[([^\[\]]+)\]`)
+	markdownDefinitionPattern = regexp.MustCompile(`^ {0,3}\[([^\[\]]+)\]:\s*(<[^>]*>|\S+)(?:\s+(?:"[^"]*"|'[^']*'|\([^)]*\)))?\s*$`)
+	markdownAutolinkPattern   = regexp.MustCompile(`<((?:https?|ftp)://[^\s<>]+|mailto:[^\s<>]+)>`)
+	markdownHTMLLinkPattern   = regexp.MustCompile(`(?i)<a\s[^>]*?href\s*=\s*["']([^"']*)["'][^>]*>(.*?)</a>`)
+)
+
+// Parse all links of a Markdown document, skipping fenced code blocks and inline code spans
+func ParseMarkdownLinks(content string) []MarkdownLink {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	code := MarkdownCodeLines(lines)
+
+	// Reference definitions first, so reference links can be resolved
+	definitions := make(map[string]string)
+	var links []MarkdownLink
+	for i, line := range lines {
+		if code[i] {
+			continue
+		}
+		if match := markdownDefinitionPattern.FindStringSubmatchIndex(line); match != nil {
+			label := line[match[2]:match[3]]
+			target := strings.Trim(line[match[4]:match[5]], "<>")
+			if _, exists := definitions[normalizeReferenceLabel(label)]; !exists {
+				definitions[normalizeReferenceLabel(label)] = target
+			}
+			links = append(links, MarkdownLink{Kind: LinkDefinition, Label: label, Target: target, Line: i + 1, Start: match[0], End: match[1],
+				TextStart: match[2], TextEnd: match[3], TargetStart: match[4], TargetEnd: match[5]})
+		}
+	}
+
+	for i, line := range lines {
+		if code[i] || markdownDefinitionPattern.MatchString(line) {
+			continue
+		}
+		links = append(links, parseMarkdownLineLinks(line, i+1, definitions)...)
+	}
+
+	sortMarkdownLinks(links)
+	return links
+}
+
+// Parse links of a single line, masking each match so later patterns do not see it again
+func parseMarkdownLineLinks(line string, number int, definitions map[string]string) []MarkdownLink {
+	var links []MarkdownLink
+	masked := []byte(maskCodeSpans(line))
+
+	mask := func(start int, end int) {
+		for i := start; i < end; i++ {
+			masked[i] = ' '
+		}
+	}
+
+	// Wikilinks and embeds: [[Note#Heading|Alias]]
+	for _, match := range markdownWikilinkPattern.FindAllSubmatchIndex(masked, -1) {
+		kind := LinkWikilink
+		if match[3] > match[2] {
+			kind = LinkEmbed
+		}
+		inner := line[match[4]:match[5]]
+		target, text := inner, inner
+		if pipe := strings.Index(inner, "|"); pipe >= 0 {
+			target, text = inner[:pipe], inner[pipe+1:]
+		}
+		links = append(links, MarkdownLink{Kind: kind, Text: strings.TrimSpace(text), Target: strings.TrimSpace(target), Line: number, Start: match[0], End: match[1],
+			TextStart: match[4], TextEnd: match[5], TargetStart: match[4], TargetEnd: match[4] + len(target)})
+		mask(match[0], match[1])
+	}
+
+	// Inline links and images, including images nested in link text: [![alt](src)](href)
+	for _, match := range markdownInlinePattern.FindAllSubmatchIndex(masked, -1) {
+		kind := LinkInline
+		if match[3] > match[2] {
+			kind = LinkImage
+		}
+		text := line[match[4]:match[5]]
+		targetStart, targetEnd := match[6], match[7]
+		if strings.HasPrefix(line[targetStart:targetEnd], "<") {
+			targetStart, targetEnd = targetStart+1, targetEnd-1
+		}
+		links = append(links, MarkdownLink{Kind: kind, Text: text, Target: line[targetStart:targetEnd], Line: number, Start: match[0], End: match[1],
+			TextStart: match[4], TextEnd: match[5], TargetStart: targetStart, TargetEnd: targetEnd})
+		for _, nested := range parseMarkdownLineLinks(text, number, definitions) {
+			nested.Start += match[4]
+			nested.End += match[4]
+			nested.TextStart += match[4]
+			nested.TextEnd += match[4]
+			nested.TargetStart += match[4]
+			nested.TargetEnd += match[4]
+			links = append(links, nested)
+		}
+		mask(match[0], match[1])
+	}
+
+	// Full and collapsed reference links: [text][label], [label][]
+	for _, match := range markdownReferencePattern.FindAllSubmatchIndex(masked, -1) {
+		text := line[match[4]:match[5]]
+		label := line[match[6]:match[7]]
+		if label == "" {
+			label = text
+		}
+		kind := LinkReference
+		if match[3] > match[2] {
+			kind = LinkImage
+		}
+		target := definitions[normalizeReferenceLabel(label)]
+		links = append(links, MarkdownLink{Kind: kind, Text: text, Target: target, Label: label, Line: number, Start: match[0], End: match[1],
+			TextStart: match[4], TextEnd: match[5], TargetStart: match[1], TargetEnd: match[1]})
+		mask(match[0], match[1])
+	}
+
+	// Shortcut reference links: [label], only when the label is defined
+	for _, match := range markdownShortcutPattern.FindAllSubmatchIndex(masked, -1) {
+		label := line[match[4]:match[5]]
+		target, defined := definitions[normalizeReferenceLabel(label)]
+		if !defined {
+			continue
+		}
+		kind := LinkReference
+		if match[3] > match[2] {
+			kind = LinkImage
+		}
+		links = append(links, MarkdownLink{Kind: kind, Text: label, Target: target, Label: label, Line: number, Start: match[0], End: match[1],
+			TextStart: match[4], TextEnd: match[5], TargetStart: match[1], TargetEnd: match[1]})
+		mask(match[0], match[1])
+	}
+
+	// Autolinks: <https://example.com>
+	for _, match := range markdownAutolinkPattern.FindAllSubmatchIndex(masked, -1) {
+		target := line[match[2]:match[3]]
+		links = append(links, MarkdownLink{Kind: LinkAutolink, Text: target, Target: target, Line: number, Start: match[0], End: match[1],
+			TextStart: match[2], TextEnd: match[2], TargetStart: match[2], TargetEnd: match[3]})
+		mask(match[0], match[1])
+	}
+
+	// HTML anchors: <a href="...">text</a>
+	for _, match := range markdownHTMLLinkPattern.FindAllSubmatchIndex(masked, -1) {
+		links = append(links, MarkdownLink{Kind: LinkHTML, Text: line[match[4]:match[5]], Target: line[match[2]:match[3]], Line: number, Start: match[0], End: match[1],
+			TextStart: match[4], TextEnd: match[5], TargetStart: match[2], TargetEnd: match[3]})
+		mask(match[0], match[1])
+	}
+
+	return links
+}
+
+// Replace inline code spans with spaces, keeping byte offsets intact
+func maskCodeSpans(line string) string {
+	if !strings.Contains(line, "`") {
+		return line
+	}
+	masked := []byte(line)
+	for i := 0; i < len(masked); {
+		if masked[i] != '`' {
+			i++
+			continue
+		}
+
+		// Opening backtick run
+		run := i
+		for run < len(masked) && masked[run] == '`' {
+			run++
+		}
+		fence := line[i:run]
+
+		// Matching closing run of the same length
+		closing := -1
+		for j := run; j < len(line); {
+			k := strings.Index(line[j:], fence)
+			if k < 0 {
+				break
+			}
+			k += j
+			end := k + len(fence)
+			if (k == 0 || line[k-1] != '`') && (end >= len(line) || line[end] != '`') {
+				closing = end
+				break
+			}
+			j = end
+			for j < len(line) && line[j] == '`' {
+				j++
+			}
+		}
+		if closing < 0 {
+			i = run
+			continue
+		}
+		for j := i; j < closing; j++ {
+			masked[j] = ' '
+		}
+		i = closing
+	}
+	return string(masked)
+}
+
+// Reference labels are case-insensitive and collapse whitespace
+func normalizeReferenceLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+// Sort links by line and position
+func sortMarkdownLinks(links []MarkdownLink) {
+	sort.SliceStable(links, func(i, j int) bool {
+		if links[i].Line != links[j].Line {
+			return links[i].Line < links[j].Line
+		}
+		return links[i].Start < links[j].Start
+	})
+}
+
+// Check if a link target points outside the document set (has a URL scheme or is protocol relative)
+var markdownSchemePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+// Check if a link target is external
+func IsExternalLink(target string) bool {
+	return markdownSchemePattern.MatchString(target) || strings.HasPrefix(target, "//")
+}
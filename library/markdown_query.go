@@ -0,0 +1,508 @@
+package library
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Markdown Note with its front matter fields
+type MarkdownNote struct {
+	File   string
+	Fields map[string]interface{}
+}
+
+// Markdown Query, parsed from terms like "status=draft tag=wordpress sort=-date group=status"
+type MarkdownQuery struct {
+	Filters []MarkdownFilter
+	Sort    []string // Field names, "-" prefix for descending
+	Group   string
+	Fields  []string // Columns to show
+	Limit   int
+
+	// Aggregates per group: sum, avg, min and max of numeric fields
+	Aggregates []MarkdownAggregate
+}
+
+// Markdown Filter on a front matter field
+type MarkdownFilter struct {
+	Field    string
+	Operator string // =, !=, >, >=, <, <=, ~ (contains) or ? (exists)
+	Value    string
+}
+
+// Markdown Aggregate of a field
+type MarkdownAggregate struct {
+	Function string
+	Field    string
+}
+
+// Group of notes with its aggregates
+type MarkdownNoteGroup struct {
+	Group      string             `json:"group"`
+	Count      int                `json:"count"`
+	Aggregates map[string]float64 `json:"aggregates,omitempty"`
+	Notes      []MarkdownNote     `json:"notes"`
+}
+
+// Query term: field, operator and value, values may be quoted
+var markdownQueryTermPattern = regexp.MustCompile(`^([A-Za-z0-9_.-]+)(!=|>=|<=|=|>|<|~)(.*)$`)
+
+// Parse a query string
+func ParseMarkdownQuery(query string) (MarkdownQuery, error) {
+	var parsed MarkdownQuery
+	for _, term := range splitQueryTerms(query) {
+		if strings.HasSuffix(term, "?") && !strings.ContainsAny(term, "=<>~") {
+			parsed.Filters = append(parsed.Filters, MarkdownFilter{Field: strings.TrimSuffix(term, "?"), Operator: "?"})
+			continue
+		}
+		match := markdownQueryTermPattern.FindStringSubmatch(term)
+		if match == nil {
+			return parsed, fmt.Errorf("invalid query term: %s", term)
+		}
+		field, operator, value := match[1], match[2], match[3]
+
+		if operator == "=" {
+			switch field {
+			case "sort":
+				parsed.Sort = strings.Split(value, ",")
+				continue
+			case "group":
+				parsed.Group = value
+				continue
+			case "fields":
+				parsed.Fields = strings.Split(value, ",")
+				continue
+			case "limit":
+				limit, err := strconv.Atoi(value)
+				if err != nil {
+					return parsed, fmt.Errorf("invalid limit: %s", value)
+				}
+				parsed.Limit = limit
+				continue
+			case "sum", "avg", "min", "max":
+				for _, aggregated := range strings.Split(value, ",") {
+					parsed.Aggregates = append(parsed.Aggregates, MarkdownAggregate{Function: field, Field: aggregated})
+				}
+				continue
+			}
+		}
+		parsed.Filters = append(parsed.Filters, MarkdownFilter{Field: field, Operator: operator, Value: value})
+	}
+	return parsed, nil
+}
+
+// Split a query on spaces, keeping quoted values together
+func splitQueryTerms(query string) []string {
+	var terms []string
+	var term strings.Builder
+	quote := rune(0)
+	for _, r := range query {
+		switch {
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == ' ' || r == '\t'):
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(r)
+		}
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms
+}
+
+// Read the front matter of every Markdown file in a directory, invalid front matter is reported as a warning
+func ReadMarkdownNotes(root string, exclude []string) ([]MarkdownNote, []string, error) {
+	vault, err := NewMarkdownVault(root, exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var notes []MarkdownNote
+	var warnings []string
+	for _, file := range vault.Notes {
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(file)))
+		if err != nil {
+			return nil, nil, err
+		}
+		note := MarkdownNote{File: file, Fields: make(map[string]interface{})}
+		frontMatter, _ := MarkdownFrontMatter(string(content))
+		if err := yaml.Unmarshal([]byte(frontMatter), &note.Fields); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", file, err))
+		}
+		if note.Fields == nil {
+			note.Fields = make(map[string]interface{})
+		}
+		notes = append(notes, note)
+	}
+	return notes, warnings, nil
+}
+
+// Value of a field, "file" and "name" are built in and "tag" falls back to "tags"
+func (n MarkdownNote) Value(field string) (interface{}, bool) {
+	switch field {
+	case "file":
+		return n.File, true
+	case "name":
+		return markdownNoteName(n.File), true
+	}
+	value, ok := n.Fields[field]
+	if !ok && field == "tag" {
+		value, ok = n.Fields["tags"]
+	}
+	return value, ok && value != nil
+}
+
+// Values of a field as strings, lists give one value per item
+func (n MarkdownNote) Values(field string) []string {
+	value, ok := n.Value(field)
+	if !ok {
+		return nil
+	}
+	if list, isList := value.([]interface{}); isList {
+		var values []string
+		for _, item := range list {
+			values = append(values, markdownFieldString(item))
+		}
+		return values
+	}
+	return []string{markdownFieldString(value)}
+}
+
+// String form of a front matter value, dates without time use the date format
+func markdownFieldString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 {
+			return v.Format(dateFormat)
+		}
+		return v.Format(time.RFC3339)
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			items = append(items, markdownFieldString(item))
+		}
+		return strings.Join(items, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Compare two values numerically when both are numbers, otherwise as strings
+func compareMarkdownValues(a string, b string) int {
+	x, errX := strconv.ParseFloat(a, 64)
+	y, errY := strconv.ParseFloat(b, 64)
+	if errX == nil && errY == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// Check if a note matches a filter, list fields match when any item matches
+func (f MarkdownFilter) Match(note MarkdownNote) bool {
+	values := note.Values(f.Field)
+	if f.Operator == "?" {
+		return len(values) > 0
+	}
+	if f.Operator == "!=" {
+		for _, value := range values {
+			if strings.EqualFold(value, f.Value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, value := range values {
+		var match bool
+		switch f.Operator {
+		case "=":
+			match = strings.EqualFold(value, f.Value)
+		case "~":
+			match = strings.Contains(strings.ToLower(value), strings.ToLower(f.Value))
+		case ">":
+			match = compareMarkdownValues(value, f.Value) > 0
+		case ">=":
+			match = compareMarkdownValues(value, f.Value) >= 0
+		case "<":
+			match = compareMarkdownValues(value, f.Value) < 0
+		case "<=":
+			match = compareMarkdownValues(value, f.Value) <= 0
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// Run a query: filter, sort and limit notes
+func (q MarkdownQuery) Run(notes []MarkdownNote) []MarkdownNote {
+	var result []MarkdownNote
+	for _, note := range notes {
+		matched := true
+		for _, filter := range q.Filters {
+			if !filter.Match(note) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, note)
+		}
+	}
+
+	// Missing values sort last
+	sort.SliceStable(result, func(i, j int) bool {
+		for _, field := range q.Sort {
+			descending := strings.HasPrefix(field, "-")
+			field = strings.TrimLeft(field, "+-")
+			a, okA := result[i].Value(field)
+			b, okB := result[j].Value(field)
+			if okA != okB {
+				return okA
+			}
+			compare := compareMarkdownValues(markdownFieldString(a), markdownFieldString(b))
+			if compare == 0 {
+				continue
+			}
+			if descending {
+				return compare > 0
+			}
+			return compare < 0
+		}
+		return false
+	})
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+	return result
+}
+
+// Group notes by a field and compute aggregates, list fields put a note in each of its groups
+func (q MarkdownQuery) GroupNotes(notes []MarkdownNote) []MarkdownNoteGroup {
+	var order []string
+	groups := make(map[string]*MarkdownNoteGroup)
+	for _, note := range notes {
+		keys := []string{""}
+		if q.Group != "" {
+			keys = note.Values(q.Group)
+			if len(keys) == 0 {
+				keys = []string{"(none)"}
+			}
+		}
+		for _, key := range keys {
+			if _, exists := groups[key]; !exists {
+				groups[key] = &MarkdownNoteGroup{Group: key}
+				order = append(order, key)
+			}
+			groups[key].Notes = append(groups[key].Notes, note)
+		}
+	}
+
+	result := make([]MarkdownNoteGroup, 0, len(order))
+	for _, key := range order {
+		group := groups[key]
+		group.Count = len(group.Notes)
+		for _, aggregate := range q.Aggregates {
+			if group.Aggregates == nil {
+				group.Aggregates = make(map[string]float64)
+			}
+			group.Aggregates[aggregate.Function+"("+aggregate.Field+")"] = aggregateMarkdownNotes(group.Notes, aggregate)
+		}
+		result = append(result, *group)
+	}
+	return result
+}
+
+// Compute an aggregate over the numeric values of a field
+func aggregateMarkdownNotes(notes []MarkdownNote, aggregate MarkdownAggregate) float64 {
+	var total, result float64
+	count := 0
+	for _, note := range notes {
+		for _, value := range note.Values(aggregate.Field) {
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			switch {
+			case count == 0:
+				result = number
+			case aggregate.Function == "min" && number < result:
+				result = number
+			case aggregate.Function == "max" && number > result:
+				result = number
+			}
+			total += number
+			count++
+		}
+	}
+
+	switch aggregate.Function {
+	case "sum":
+		return total
+	case "avg":
+		if count == 0 {
+			return 0
+		}
+		return total / float64(count)
+	}
+	return result
+}
+
+// Columns shown for query results: requested fields, or file, title and the fields used by the query
+func (q MarkdownQuery) Columns() []string {
+	if len(q.Fields) > 0 {
+		return q.Fields
+	}
+	columns := []string{"file", "title"}
+	add := func(field string) {
+		field = strings.TrimLeft(field, "+-")
+		if field != "" && !SliceContainsString(columns, field) {
+			columns = append(columns, field)
+		}
+	}
+	for _, filter := range q.Filters {
+		add(filter.Field)
+	}
+	for _, field := range q.Sort {
+		add(field)
+	}
+	return columns
+}
+
+// Render notes as a Markdown index with links relative to the index file
+func MarkdownQueryIndex(q MarkdownQuery, groups []MarkdownNoteGroup, indexPath string, root string) string {
+	columns := q.Columns()
+	base := root
+	if indexPath != "" {
+		base = filepath.Dir(indexPath)
+	}
+
+	var index strings.Builder
+	for i, group := range groups {
+		if q.Group != "" {
+			if i > 0 {
+				index.WriteString("\n")
+			}
+			fmt.Fprintf(&index, "## %s (%d)\n\n", group.Group, group.Count)
+		}
+		index.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+		index.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+		for _, note := range group.Notes {
+			var cells []string
+			for _, column := range columns {
+				cell := strings.Join(note.Values(column), ", ")
+				if column == "file" {
+					link, err := filepath.Rel(base, filepath.Join(root, filepath.FromSlash(note.File)))
+					if err != nil {
+						link = note.File
+					}
+					cell = fmt.Sprintf("[%s](%s)", markdownNoteName(note.File), MarkdownEscapeLinkTarget(filepath.ToSlash(link)))
+				}
+				cells = append(cells, strings.ReplaceAll(cell, "|", `\|`))
+			}
+			index.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+	}
+	return index.String()
+}
+
+// Escape a link target for use in a Markdown inline link
+func MarkdownEscapeLinkTarget(target string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(target)
+}
+
+// Print query results as table, json or markdown
+func PrintMarkdownQuery(q MarkdownQuery, notes []MarkdownNote, format string) {
+	groups := q.GroupNotes(notes)
+	columns := q.Columns()
+
+	switch format {
+	case "json":
+		rows := func(notes []MarkdownNote) []map[string]interface{} {
+			result := []map[string]interface{}{}
+			for _, note := range notes {
+				row := map[string]interface{}{"file": note.File}
+				for field, value := range note.Fields {
+					if _, isTime := value.(time.Time); isTime {
+						value = markdownFieldString(value)
+					}
+					row[field] = value
+				}
+				result = append(result, row)
+			}
+			return result
+		}
+		if q.Group == "" && len(q.Aggregates) == 0 {
+			PrintJSON(rows(notes))
+			return
+		}
+		var output []map[string]interface{}
+		for _, group := range groups {
+			output = append(output, map[string]interface{}{"group": group.Group, "count": group.Count, "aggregates": group.Aggregates, "notes": rows(group.Notes)})
+		}
+		if output == nil {
+			output = []map[string]interface{}{}
+		}
+		PrintJSON(output)
+
+	case "markdown":
+		fmt.Print(MarkdownQueryIndex(q, groups, "", ""))
+
+	default:
+		if q.Group != "" || len(q.Aggregates) > 0 {
+			headers := []string{"GROUP", "COUNT"}
+			for _, aggregate := range q.Aggregates {
+				headers = append(headers, strings.ToUpper(aggregate.Function+"("+aggregate.Field+")"))
+			}
+			var rows [][]string
+			for _, group := range groups {
+				row := []string{group.Group, strconv.Itoa(group.Count)}
+				for _, aggregate := range q.Aggregates {
+					row = append(row, strconv.FormatFloat(group.Aggregates[aggregate.Function+"("+aggregate.Field+")"], 'f', -1, 64))
+				}
+				rows = append(rows, row)
+			}
+			PrintTable(headers, rows)
+		} else {
+			var headers []string
+			for _, column := range columns {
+				headers = append(headers, strings.ToUpper(column))
+			}
+			var rows [][]string
+			for _, note := range notes {
+				var row []string
+				for _, column := range columns {
+					row = append(row, strings.Join(note.Values(column), ", "))
+				}
+				rows = append(rows, row)
+			}
+			PrintTable(headers, rows)
+		}
+		fmt.Println("🐙 There are", len(notes), "notes matching the query")
+	}
+}
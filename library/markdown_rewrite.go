@@ -0,0 +1,335 @@
+package library
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Link Rewrite Options
+type LinkRewriteOptions struct {
+	Strip    bool   // Replace links with their text, images are kept
+	From     string // Replace target prefix From ...
+	To       string // ... with To
+	Relative bool   // Convert absolute links under BaseURL to relative paths
+	BaseURL  string
+
+	// Restrict changes to links matching a host or any of the patterns
+	Hostname string
+	Patterns []*regexp.Regexp
+}
+
+// File Link is a link with the file it was found in
+type FileLink struct {
+	File string `json:"file"`
+	MarkdownLink
+}
+
+// Check if a link is selected by the host and pattern filters
+func (o LinkRewriteOptions) Matches(link MarkdownLink) bool {
+	if o.Hostname != "" {
+		parsed, err := url.Parse(link.Target)
+		if err != nil || parsed.Hostname() == "" {
+			return false
+		}
+		host := strings.ToLower(parsed.Hostname())
+		wanted := strings.ToLower(o.Hostname)
+		if host != wanted && !strings.HasSuffix(host, "."+wanted) {
+			return false
+		}
+	}
+	if len(o.Patterns) > 0 {
+		for _, pattern := range o.Patterns {
+			if pattern.MatchString(link.Target) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Remove Link from Markdown content, keeping the link text and leaving images untouched
+func MarkdownRemoveLink(markdown string) string {
+	content, _ := MarkdownRewriteLinks(markdown, "", LinkRewriteOptions{Strip: true})
+	return content
+}
+
+// Strip or rewrite the links of a Markdown document. file is the path relative to the
+// root, used to compute relative links. Returns the new content and the number of changes.
+func MarkdownRewriteLinks(content string, file string, options LinkRewriteOptions) (string, int) {
+	links := ParseMarkdownLinks(content)
+	if len(links) == 0 {
+		return content, 0
+	}
+
+	byLine := make(map[int][]MarkdownLink)
+	for _, link := range links {
+		byLine[link.Line] = append(byLine[link.Line], link)
+	}
+
+	// Definitions still used by kept images and filtered out references survive stripping
+	used := make(map[string]bool)
+	for _, link := range links {
+		if link.Label != "" && link.Kind != LinkDefinition && (link.Kind == LinkImage || !options.Matches(link)) {
+			used[normalizeReferenceLabel(link.Label)] = true
+		}
+	}
+
+	rewriter := &linkRewriter{options: options, file: file, used: used}
+	lines := strings.Split(content, "\n")
+	var output []string
+	for i, line := range lines {
+		lineLinks, ok := byLine[i+1]
+		if !ok {
+			output = append(output, line)
+			continue
+		}
+
+		// Keep the CR of CRLF line endings outside of the link offsets
+		cr := ""
+		if strings.HasSuffix(line, "\r") {
+			line, cr = strings.TrimSuffix(line, "\r"), "\r"
+		}
+
+		removed := rewriter.removed
+		rewritten := rewriter.render(line, 0, len(line), lineLinks)
+
+		// Drop lines that only held a stripped reference definition
+		if rewriter.removed > removed && strings.TrimSpace(rewritten) == "" {
+			continue
+		}
+		output = append(output, rewritten+cr)
+	}
+
+	return strings.Join(output, "\n"), rewriter.changes
+}
+
+// Link rewriter state for a single document
+type linkRewriter struct {
+	options LinkRewriteOptions
+	file    string
+	changes int
+	removed int
+	used    map[string]bool // Normalized labels of references that are kept
+}
+
+// Render the segment [start, end) of a line applying link changes, nested links are handled recursively
+func (r *linkRewriter) render(line string, start int, end int, links []MarkdownLink) string {
+	var out strings.Builder
+	position := start
+	for i, link := range links {
+		if link.Start < position || link.End > end {
+			continue
+		}
+		out.WriteString(line[position:link.Start])
+
+		var children []MarkdownLink
+		for j, child := range links {
+			if j != i && child.Start >= link.TextStart && child.End <= link.TextEnd && child.Start > link.Start {
+				children = append(children, child)
+			}
+		}
+		text := r.render(line, link.TextStart, link.TextEnd, children)
+		out.WriteString(r.replace(line, link, text))
+		position = link.End
+	}
+	out.WriteString(line[position:end])
+	return out.String()
+}
+
+// Replacement of a single link, text is the already rendered link text
+func (r *linkRewriter) replace(line string, link MarkdownLink, text string) string {
+	target := line[link.TargetStart:link.TargetEnd]
+	if link.Kind == LinkWikilink || link.Kind == LinkEmbed || !r.options.Matches(link) {
+		return assembleMarkdownLink(line, link, text, target)
+	}
+
+	if r.options.Strip && link.Kind != LinkImage && !(link.Kind == LinkDefinition && r.used[normalizeReferenceLabel(link.Label)]) {
+		r.changes++
+		switch link.Kind {
+		case LinkAutolink:
+			return link.Target
+		case LinkDefinition:
+			r.removed++
+			return ""
+		default:
+			return text
+		}
+	}
+
+	if newTarget, ok := r.rewriteTarget(link.Target); ok && link.TargetEnd > link.TargetStart {
+		r.changes++
+		// Autolinks can only hold absolute URLs
+		if link.Kind == LinkAutolink && !IsExternalLink(newTarget) {
+			return "[" + link.Target + "](" + newTarget + ")"
+		}
+		return assembleMarkdownLink(line, link, text, newTarget)
+	}
+	return assembleMarkdownLink(line, link, text, target)
+}
+
+// Rewrite a link target, returns false when the target is unchanged
+func (r *linkRewriter) rewriteTarget(target string) (string, bool) {
+	if r.options.From != "" && strings.HasPrefix(target, r.options.From) {
+		return r.options.To + strings.TrimPrefix(target, r.options.From), true
+	}
+
+	if r.options.Relative && r.options.BaseURL != "" && strings.HasPrefix(target, r.options.BaseURL) {
+		rest := strings.TrimPrefix(target, r.options.BaseURL)
+		suffix := ""
+		if index := strings.IndexAny(rest, "?#"); index >= 0 {
+			rest, suffix = rest[:index], rest[index:]
+		}
+		destination := strings.Trim(rest, "/")
+		if destination == "" {
+			destination = "."
+		}
+		relative, err := filepath.Rel(filepath.Dir(filepath.FromSlash(r.file)), filepath.FromSlash(destination))
+		if err != nil {
+			return target, false
+		}
+		relative = filepath.ToSlash(relative)
+		if strings.HasSuffix(rest, "/") || relative == "." {
+			relative += "/"
+		}
+		return relative + suffix, true
+	}
+
+	return target, false
+}
+
+// Rebuild a link replacing its text and target spans
+func assembleMarkdownLink(line string, link MarkdownLink, text string, target string) string {
+	type span struct {
+		start, end int
+		value      string
+	}
+	spans := []span{{link.TextStart, link.TextEnd, text}, {link.TargetStart, link.TargetEnd, target}}
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	var out strings.Builder
+	position := link.Start
+	for _, s := range spans {
+		if s.start < position {
+			continue
+		}
+		out.WriteString(line[position:s.start])
+		out.WriteString(s.value)
+		position = s.end
+	}
+	out.WriteString(line[position:link.End])
+	return out.String()
+}
+
+// Collect Markdown files of a path, which may be a single file
+func collectMarkdownFiles(path string, exclude []string) (string, []string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", nil, err
+	}
+	if !info.IsDir() {
+		return filepath.Dir(path), []string{path}, nil
+	}
+
+	files, err := FileFilter{Exclude: exclude}.CollectFiles(path)
+	if err != nil {
+		return "", nil, err
+	}
+	var markdown []string
+	for _, file := range files {
+		if IsMarkdownFile(file) && !strings.Contains(filepath.ToSlash(file), "/.obsidian/") {
+			markdown = append(markdown, file)
+		}
+	}
+	return path, markdown, nil
+}
+
+// Strip or rewrite links in a Markdown file or directory
+func MarkdownRewriteLinksInPath(path string, exclude []string, options LinkRewriteOptions, dryRun bool) error {
+	root, files, err := collectMarkdownFiles(path, exclude)
+	if err != nil {
+		return err
+	}
+
+	var total int
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, file)
+		updated, changes := MarkdownRewriteLinks(string(content), filepath.ToSlash(rel), options)
+		if changes == 0 {
+			continue
+		}
+		total += changes
+
+		if dryRun {
+			fmt.Print(UnifiedDiff(splitLines(string(content)), splitLines(updated), "a/"+filepath.ToSlash(rel), "b/"+filepath.ToSlash(rel), 0))
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, []byte(updated), info.Mode().Perm()); err != nil {
+			return err
+		}
+		fmt.Println("✅", changes, "links changed in", file)
+	}
+
+	if dryRun {
+		fmt.Println("🔍 Dry run completed.", total, "links would be changed")
+	} else {
+		fmt.Println("✅ Successfully changed", total, "links in", path)
+	}
+	return nil
+}
+
+// List links in a Markdown file or directory matching the filters
+func MarkdownListLinks(path string, exclude []string, options LinkRewriteOptions) ([]FileLink, error) {
+	root, files, err := collectMarkdownFiles(path, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []FileLink
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		rel, _ := filepath.Rel(root, file)
+		for _, link := range ParseMarkdownLinks(string(content)) {
+			if options.Matches(link) {
+				links = append(links, FileLink{File: filepath.ToSlash(rel), MarkdownLink: link})
+			}
+		}
+	}
+	return links, nil
+}
+
+// Print links as table or JSON
+func PrintFileLinks(links []FileLink, format string) {
+	if format == "json" {
+		if links == nil {
+			links = []FileLink{}
+		}
+		PrintJSON(links)
+		return
+	}
+
+	var rows [][]string
+	for _, link := range links {
+		rows = append(rows, []string{fmt.Sprintf("%s:%d", link.File, link.Line), link.Kind, link.Target, link.Text})
+	}
+	PrintTable([]string{"FILE", "KIND", "TARGET", "TEXT"}, rows)
+	fmt.Println("🐙 There are", len(links), "links")
+}
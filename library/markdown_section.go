@@ -0,0 +1,140 @@
+package library
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Section edit operations
+const (
+	SectionReplace = "replace" // Replace the section body, the heading is kept
+	SectionAppend  = "append"  // Append to the end of the section, after its subsections
+	SectionPrepend = "prepend" // Insert at the start of the section body, after the heading
+	SectionInsert  = "insert"  // Insert before the section, e.g. a new sibling section
+)
+
+// Markdown Section is a heading with its content up to the next heading of the same or higher level
+type MarkdownSection struct {
+	Heading MarkdownHeading
+	Start   int // Line index of the heading
+	End     int // Line index after the last line of the section
+}
+
+// Find a section by heading path, e.g. "Installation > Install from source".
+// Each part matches a heading text or anchor, case-insensitively, and may be prefixed with "#" to require a level.
+// Later parts must be subsections of the previous one.
+func FindMarkdownSection(content string, headingPath string) (MarkdownSection, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	headings := ParseMarkdownHeadings(content)
+
+	parent := MarkdownSection{Start: -1, End: len(lines)}
+	for _, part := range strings.Split(headingPath, ">") {
+		part = strings.TrimSpace(part)
+		level := len(part) - len(strings.TrimLeft(part, "#"))
+		part = strings.TrimSpace(strings.TrimLeft(part, "#"))
+
+		found := false
+		for i, heading := range headings {
+			index := heading.Line - 1
+			if index <= parent.Start || index >= parent.End || (parent.Start >= 0 && heading.Level <= parent.Heading.Level) {
+				continue
+			}
+			if level > 0 && heading.Level != level {
+				continue
+			}
+			if !strings.EqualFold(MarkdownPlainText(heading.Text), part) && !strings.EqualFold(heading.Text, part) && heading.Anchor != MarkdownAnchor(part) {
+				continue
+			}
+
+			// The section ends at the next heading of the same or a higher level
+			end := len(lines)
+			for _, next := range headings[i+1:] {
+				if next.Level <= heading.Level {
+					end = next.Line - 1
+					break
+				}
+			}
+			parent = MarkdownSection{Heading: heading, Start: index, End: end}
+			found = true
+			break
+		}
+		if !found {
+			return parent, fmt.Errorf("heading not found: %s", headingPath)
+		}
+	}
+	return parent, nil
+}
+
+// Content of a section without its heading line, subsections included
+func MarkdownSectionContent(content string, headingPath string) (string, error) {
+	section, err := FindMarkdownSection(content, headingPath)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	return strings.Trim(strings.Join(lines[section.Start+1:section.End], "\n"), "\n") + "\n", nil
+}
+
+// Replace, append or prepend to a section, or insert before it. Line endings of the document are kept.
+func MarkdownEditSection(content string, headingPath string, operation string, text string) (string, error) {
+	section, err := FindMarkdownSection(content, headingPath)
+	if err != nil {
+		return "", err
+	}
+
+	crlf := DetectLineEndings([]byte(content)) == "crlf"
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	block := strings.Split(strings.Trim(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), "\n")
+
+	// Last non blank line of the section
+	last := section.End - 1
+	for last > section.Start && strings.TrimSpace(lines[last]) == "" {
+		last--
+	}
+
+	var updated []string
+	switch operation {
+	case SectionReplace:
+		updated = append(updated, lines[:section.Start+1]...)
+		updated = append(updated, "")
+		updated = append(updated, block...)
+		updated = append(updated, markdownSectionTail(lines, section.End)...)
+	case SectionAppend:
+		updated = append(updated, lines[:last+1]...)
+		updated = append(updated, "")
+		updated = append(updated, block...)
+		updated = append(updated, markdownSectionTail(lines, section.End)...)
+	case SectionPrepend:
+		updated = append(updated, lines[:section.Start+1]...)
+		updated = append(updated, "")
+		updated = append(updated, block...)
+		if section.Start+1 < len(lines) && strings.TrimSpace(lines[section.Start+1]) != "" {
+			updated = append(updated, "")
+		}
+		updated = append(updated, lines[section.Start+1:]...)
+	case SectionInsert:
+		updated = append(updated, lines[:section.Start]...)
+		updated = append(updated, block...)
+		updated = append(updated, "")
+		updated = append(updated, lines[section.Start:]...)
+	default:
+		return "", fmt.Errorf("unsupported section operation: %s (replace|append|prepend|insert)", operation)
+	}
+
+	result := strings.Join(updated, "\n")
+	if crlf {
+		result = strings.ReplaceAll(result, "\n", "\r\n")
+	}
+	return result, nil
+}
+
+// Lines after a section, separated by a blank line when another heading follows
+func markdownSectionTail(lines []string, end int) []string {
+	if end >= len(lines) {
+		if len(lines) > 0 && lines[len(lines)-1] == "" {
+			return []string{""}
+		}
+		return nil
+	}
+	return append([]string{""}, lines[end:]...)
+}
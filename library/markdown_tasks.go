@@ -0,0 +1,220 @@
+package library
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Task statuses
+const (
+	TaskOpen      = "open"
+	TaskDone      = "done"
+	TaskCancelled = "cancelled"
+)
+
+// Markdown Task is a checkbox list item
+type MarkdownTask struct {
+	File      string   `json:"file"`
+	Line      int      `json:"line"`
+	Status    string   `json:"status"`
+	Text      string   `json:"text"`
+	Due       string   `json:"due,omitempty"`
+	Scheduled string   `json:"scheduled,omitempty"`
+	Completed string   `json:"completed,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+}
+
+// Markdown Task Filter, empty fields match every task
+type MarkdownTaskFilter struct {
+	Status    string // open, done, cancelled or all
+	Tags      []string
+	DateStart time.Time // Due date window, both inclusive
+	DateEnd   time.Time
+}
+
+// Task patterns: "- [ ] text", due "📅 2026-10-20", scheduled "⏳ 2026-10-18", done "✅ 2026-10-19" and "#tags"
+var (
+	markdownTaskPattern      = regexp.MustCompile(`^\s*(?:[-*+]|\d{1,9}[.)])\s+\[([ xX\-])\]\s+(.*)$`)
+	markdownTaskDuePattern   = regexp.MustCompile(`📅\s*(\d{4}-\d{2}-\d{2})`)
+	markdownTaskSchedPattern = regexp.MustCompile(`⏳\s*(\d{4}-\d{2}-\d{2})`)
+	markdownTaskDonePattern  = regexp.MustCompile(`✅\s*(\d{4}-\d{2}-\d{2})`)
+	markdownTaskTagPattern   = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_/-]*[\p{L}_/-][\p{L}\p{N}_/-]*)`)
+)
+
+// Parse the tasks of a Markdown document, code blocks are skipped
+func ParseMarkdownTasks(content string, file string) []MarkdownTask {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	code := MarkdownCodeLines(lines)
+
+	var tasks []MarkdownTask
+	for i, line := range lines {
+		if code[i] {
+			continue
+		}
+		match := markdownTaskPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		task := MarkdownTask{File: file, Line: i + 1, Status: TaskOpen, Text: strings.TrimSpace(match[2])}
+		switch match[1] {
+		case "x", "X":
+			task.Status = TaskDone
+		case "-":
+			task.Status = TaskCancelled
+		}
+		if due := markdownTaskDuePattern.FindStringSubmatch(task.Text); due != nil {
+			task.Due = due[1]
+		}
+		if scheduled := markdownTaskSchedPattern.FindStringSubmatch(task.Text); scheduled != nil {
+			task.Scheduled = scheduled[1]
+		}
+		if completed := markdownTaskDonePattern.FindStringSubmatch(task.Text); completed != nil {
+			task.Completed = completed[1]
+		}
+		masked := maskCodeSpans(task.Text)
+		for _, tag := range markdownTaskTagPattern.FindAllStringSubmatch(masked, -1) {
+			if !SliceContainsString(task.Tags, tag[1]) {
+				task.Tags = append(task.Tags, tag[1])
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// Collect the tasks of the Markdown files in a file or directory, the report file is skipped
+func CollectMarkdownTasks(path string, exclude []string, report string, filter MarkdownTaskFilter) ([]MarkdownTask, error) {
+	root, files, err := collectMarkdownFiles(path, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []MarkdownTask
+	for _, file := range files {
+		if report != "" && IsSamePath(file, report) {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		rel, _ := filepath.Rel(root, file)
+		for _, task := range ParseMarkdownTasks(string(content), filepath.ToSlash(rel)) {
+			if filter.Match(task) {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	return tasks, nil
+}
+
+// Check if a task matches the filter, a date window only matches tasks with a due date
+func (f MarkdownTaskFilter) Match(task MarkdownTask) bool {
+	if f.Status != "" && f.Status != "all" && f.Status != task.Status {
+		return false
+	}
+	for _, tag := range f.Tags {
+		tag = strings.TrimPrefix(tag, "#")
+		found := false
+		for _, taskTag := range task.Tags {
+			if strings.EqualFold(taskTag, tag) || strings.HasPrefix(strings.ToLower(taskTag), strings.ToLower(tag)+"/") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !f.DateStart.IsZero() || !f.DateEnd.IsZero() {
+		due, err := time.Parse(dateFormat, task.Due)
+		if err != nil || !dateInRange(due, f.DateStart, f.DateEnd) {
+			return false
+		}
+	}
+	return true
+}
+
+// Render tasks as a Markdown report grouped by file, with a summary and overdue tasks first.
+// Links are relative to the report file when given, otherwise to the root the tasks were collected from.
+func MarkdownTasksReport(tasks []MarkdownTask, root string, report string, today time.Time) string {
+	if info, err := os.Stat(root); err == nil && !info.IsDir() {
+		root = filepath.Dir(root)
+	}
+	link := func(file string) string {
+		if report != "" {
+			if rel, err := filepath.Rel(filepath.Dir(report), filepath.Join(root, filepath.FromSlash(file))); err == nil {
+				file = filepath.ToSlash(rel)
+			}
+		}
+		return MarkdownEscapeLinkTarget(file)
+	}
+
+	counts := make(map[string]int)
+	var overdue []MarkdownTask
+	for _, task := range tasks {
+		counts[task.Status]++
+		if task.Status == TaskOpen && task.Due != "" && task.Due < today.Format(dateFormat) {
+			overdue = append(overdue, task)
+		}
+	}
+
+	checkbox := map[string]string{TaskOpen: "[ ]", TaskDone: "[x]", TaskCancelled: "[-]"}
+	item := func(task MarkdownTask, withFile bool) string {
+		location := fmt.Sprintf("line %d", task.Line)
+		if withFile {
+			location = fmt.Sprintf("[%s](%s) line %d", task.File, link(task.File), task.Line)
+		}
+		return fmt.Sprintf("- %s %s (%s)\n", checkbox[task.Status], task.Text, location)
+	}
+
+	var output strings.Builder
+	output.WriteString("# Tasks\n\n")
+	fmt.Fprintf(&output, "%d open, %d done, %d cancelled, %d overdue\n", counts[TaskOpen], counts[TaskDone], counts[TaskCancelled], len(overdue))
+
+	if len(overdue) > 0 {
+		sort.SliceStable(overdue, func(i, j int) bool {
+			return overdue[i].Due < overdue[j].Due
+		})
+		output.WriteString("\n## Overdue\n\n")
+		for _, task := range overdue {
+			output.WriteString(item(task, true))
+		}
+	}
+
+	file := ""
+	for _, task := range tasks {
+		if task.File != file {
+			file = task.File
+			fmt.Fprintf(&output, "\n## [%s](%s)\n\n", file, link(file))
+		}
+		output.WriteString(item(task, false))
+	}
+	return output.String()
+}
+
+// Print tasks as table, json or markdown
+func PrintMarkdownTasks(tasks []MarkdownTask, format string) {
+	switch format {
+	case "json":
+		if tasks == nil {
+			tasks = []MarkdownTask{}
+		}
+		PrintJSON(tasks)
+	case "markdown":
+		fmt.Print(MarkdownTasksReport(tasks, "", "", time.Now()))
+	default:
+		var rows [][]string
+		for _, task := range tasks {
+			rows = append(rows, []string{fmt.Sprintf("%s:%d", task.File, task.Line), task.Status, task.Due, strings.Join(task.Tags, ","), task.Text})
+		}
+		PrintTable([]string{"FILE", "STATUS", "DUE", "TAGS", "TASK"}, rows)
+		fmt.Println("🐙 There are", len(tasks), "tasks")
+	}
+}
@@ -0,0 +1,102 @@
+package library
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Table of contents markers
+const (
+	MarkdownTocStart = "<!-- toc -->"
+	MarkdownTocEnd   = "<!-- tocstop -->"
+)
+
+// Generate a Markdown table of contents for headings between minDepth and maxDepth
+func MarkdownGenerateToc(content string, minDepth int, maxDepth int) string {
+	if minDepth <= 0 {
+		minDepth = 1
+	}
+	if maxDepth <= 0 || maxDepth > 6 {
+		maxDepth = 6
+	}
+
+	// Ignore headings inside an existing table of contents
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	startLine, endLine := markdownTocRange(lines)
+
+	var toc strings.Builder
+	for _, heading := range ParseMarkdownHeadings(content) {
+		if heading.Level < minDepth || heading.Level > maxDepth {
+			continue
+		}
+		if startLine >= 0 && heading.Line-1 > startLine && heading.Line-1 < endLine {
+			continue
+		}
+		indent := strings.Repeat("  ", heading.Level-minDepth)
+		fmt.Fprintf(&toc, "%s- [%s](#%s)\n", indent, MarkdownPlainText(heading.Text), heading.Anchor)
+	}
+	return toc.String()
+}
+
+// Insert or refresh the table of contents between the markers.
+// Without markers the table of contents is inserted after the first level 1 heading, or at the top.
+// Lines keep their line endings, inserted lines use CRLF in CRLF documents.
+func MarkdownUpdateToc(content string, minDepth int, maxDepth int) string {
+	cr := ""
+	if DetectLineEndings([]byte(content)) == "crlf" {
+		cr = "\r"
+	}
+	toc := strings.ReplaceAll(MarkdownGenerateToc(content, minDepth, maxDepth), "\n", cr+"\n")
+	block := MarkdownTocStart + cr + "\n" + cr + "\n" + toc + cr + "\n" + MarkdownTocEnd
+
+	lines := strings.Split(content, "\n")
+	startLine, endLine := markdownTocRange(lines)
+	if startLine >= 0 {
+		if strings.HasSuffix(lines[endLine], "\r") {
+			block += "\r"
+		}
+		updated := append([]string{}, lines[:startLine]...)
+		updated = append(updated, block)
+		updated = append(updated, lines[endLine+1:]...)
+		return strings.Join(updated, "\n")
+	}
+
+	// Insert after the first level 1 heading
+	for _, heading := range ParseMarkdownHeadings(content) {
+		if heading.Level == 1 {
+			updated := append([]string{}, lines[:heading.Line]...)
+			updated = append(updated, cr, block+cr)
+			updated = append(updated, lines[heading.Line:]...)
+			return strings.Join(updated, "\n")
+		}
+	}
+	return block + cr + "\n" + cr + "\n" + content
+}
+
+// Update the table of contents of a file in place, in check mode only report if it is outdated
+func MarkdownUpdateTocFile(path string, minDepth int, maxDepth int, check bool) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	updated := MarkdownUpdateToc(string(content), minDepth, maxDepth)
+	if updated == string(content) {
+		return false, nil
+	}
+	if check {
+		return true, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return true, err
+	}
+	return true, os.WriteFile(path, []byte(updated), info.Mode().Perm())
+}
+
+// Line index of the table of contents markers outside code blocks, -1 when missing
+func markdownTocRange(lines []string) (int, int) {
+	return markdownMarkerRange(lines, MarkdownTocStart, MarkdownTocEnd)
+}
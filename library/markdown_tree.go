@@ -0,0 +1,203 @@
+package library
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// File tree markers for in-place updates
+const (
+	MarkdownTreeStart = "<!-- tree -->"
+	MarkdownTreeEnd   = "<!-- treestop -->"
+)
+
+// Markdown Tree Options
+type MarkdownTreeOptions struct {
+	Ignore    []string
+	MaxDepth  int    // 0 for unlimited, 1 lists only the root entries
+	DirsFirst bool   // List directories before files
+	Style     string // markdown (default), ascii or unicode
+	LinkBase  string // Directory links are relative to, defaults to the root
+}
+
+// Default ignored files and directories
+var markdownTreeIgnore = []string{".git", ".github", ".vscode", ".idea", ".obsidian", ".gitignore", ".gitkeep", ".DS_Store"}
+
+// Generate File Tree from Directory as a Markdown list of links
+func MarkdownGenerateFileTree(path string, ignore []string) string {
+	tree, err := MarkdownFileTree(path, MarkdownTreeOptions{Ignore: ignore})
+	if err != nil {
+		return ""
+	}
+	return tree
+}
+
+// Generate the file tree of a directory as a Markdown list with relative links, or as an ASCII or Unicode tree
+func MarkdownFileTree(root string, options MarkdownTreeOptions) (string, error) {
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	if options.LinkBase == "" {
+		options.LinkBase = root
+	}
+
+	var connectors [4]string
+	switch options.Style {
+	case "", "markdown":
+	case "ascii":
+		connectors = [4]string{"|-- ", "`-- ", "|   ", "    "}
+	case "unicode":
+		connectors = [4]string{"├── ", "└── ", "│   ", "    "}
+	default:
+		return "", fmt.Errorf("unsupported tree style: %s (markdown|ascii|unicode)", options.Style)
+	}
+
+	var tree strings.Builder
+	if options.Style == "ascii" || options.Style == "unicode" {
+		tree.WriteString(filepath.Base(filepath.Clean(root)) + "/\n")
+	}
+
+	// Links need both paths absolute, a relative link base can't be related to an absolute root
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	if options.LinkBase, err = filepath.Abs(options.LinkBase); err != nil {
+		return "", err
+	}
+
+	var walk func(dir string, depth int, prefix string) error
+	walk = func(dir string, depth int, prefix string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+
+		var visible []os.DirEntry
+		for _, entry := range entries {
+			if !SliceContainsString(markdownTreeIgnore, entry.Name()) && !SliceContainsString(options.Ignore, entry.Name()) {
+				visible = append(visible, entry)
+			}
+		}
+		sort.SliceStable(visible, func(i, j int) bool {
+			if options.DirsFirst && visible[i].IsDir() != visible[j].IsDir() {
+				return visible[i].IsDir()
+			}
+			return strings.ToLower(visible[i].Name()) < strings.ToLower(visible[j].Name())
+		})
+
+		for i, entry := range visible {
+			path := filepath.Join(dir, entry.Name())
+			last := i == len(visible)-1
+			descend := entry.IsDir() && (options.MaxDepth <= 0 || depth < options.MaxDepth)
+
+			switch options.Style {
+			case "ascii", "unicode":
+				connector, next := connectors[0], connectors[2]
+				if last {
+					connector, next = connectors[1], connectors[3]
+				}
+				name := entry.Name()
+				if entry.IsDir() {
+					name += "/"
+				}
+				tree.WriteString(prefix + connector + name + "\n")
+				if descend {
+					if err := walk(path, depth+1, prefix+next); err != nil {
+						return err
+					}
+				}
+			default:
+				if entry.IsDir() {
+					fmt.Fprintf(&tree, "%s- %s\n", prefix, entry.Name())
+					if descend {
+						if err := walk(path, depth+1, prefix+"  "); err != nil {
+							return err
+						}
+					}
+					continue
+				}
+				link, err := filepath.Rel(options.LinkBase, path)
+				if err != nil {
+					return err
+				}
+				target := (&url.URL{Path: filepath.ToSlash(link)}).String()
+				label := strings.NewReplacer("[", `\[`, "]", `\]`).Replace(entry.Name())
+				fmt.Fprintf(&tree, "%s- [%s](%s)\n", prefix, label, target)
+			}
+		}
+		return nil
+	}
+
+	if err := walk(root, 1, ""); err != nil {
+		return "", err
+	}
+	return tree.String(), nil
+}
+
+// Update the file tree of a Markdown file between the tree markers, or in the section of a heading path.
+// Links are relative to the file. Returns true when the file changed.
+func MarkdownUpdateTreeFile(file string, root string, heading string, options MarkdownTreeOptions) (bool, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+
+	options.LinkBase = filepath.Dir(file)
+	tree, err := MarkdownFileTree(root, options)
+	if err != nil {
+		return false, err
+	}
+	if options.Style == "ascii" || options.Style == "unicode" {
+		tree = "```text\n" + tree + "```\n"
+	}
+
+	var updated string
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	start, end := markdownMarkerRange(lines, MarkdownTreeStart, MarkdownTreeEnd)
+	switch {
+	case start >= 0:
+		block := append([]string{}, lines[:start+1]...)
+		block = append(block, "", strings.TrimSuffix(tree, "\n"), "")
+		block = append(block, lines[end:]...)
+		updated = strings.Join(block, "\n")
+	case heading != "":
+		updated, err = MarkdownEditSection(string(content), heading, SectionReplace, tree)
+		if err != nil {
+			return false, err
+		}
+	default:
+		return false, fmt.Errorf("no %s marker or --heading in %s", MarkdownTreeStart, file)
+	}
+
+	if updated == string(content) {
+		return false, nil
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(file, []byte(updated), info.Mode().Perm())
+}
+
+// Line index of a pair of markers outside code blocks, -1 when missing
+func markdownMarkerRange(lines []string, startMarker string, endMarker string) (int, int) {
+	code := MarkdownCodeLines(lines)
+	start := -1
+	for i, line := range lines {
+		if code[i] {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if start < 0 && trimmed == startMarker {
+			start = i
+		} else if start >= 0 && trimmed == endMarker {
+			return start, i
+		}
+	}
+	return -1, -1
+}
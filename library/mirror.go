@@ -0,0 +1,369 @@
+package library
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Mirror Options
+type MirrorOptions struct {
+	Delete   bool
+	Checksum bool
+	DryRun   bool
+	Excludes []string
+}
+
+// Mirror Action
+type MirrorAction struct {
+	Item string // rsync style itemised change, e.g. ">f.st......"
+	Path string
+	Kind string // mkdir, copy, symlink, delete
+	Size int64
+}
+
+// Mirror using source and destination from rsync.json, both must be local
+func MirrorFromConfig(config RsyncConfig, options MirrorOptions) error {
+	if config.Source.Remote != "" || config.Destination.Remote != "" {
+		return fmt.Errorf("native mirror only supports local source and destination, use --rsync for remotes")
+	}
+	return Mirror(config.Source.Path, config.Destination.Path, options)
+}
+
+// Mirror the contents of source into destination (one-way), like `rsync -a source/ destination/`
+func Mirror(source string, destination string, options MirrorOptions) error {
+	actions, err := PlanMirror(source, destination, options)
+	if err != nil {
+		return err
+	}
+
+	if len(actions) == 0 {
+		fmt.Println("✅ Destination is up to date:", destination)
+		return nil
+	}
+
+	if options.DryRun {
+		for _, action := range actions {
+			fmt.Println(action.Item, action.Path)
+		}
+		fmt.Printf("🔍 Dry run completed. %d changes would be made to %s\n", len(actions), destination)
+		return nil
+	}
+
+	var transferred int64
+	for i, action := range actions {
+		fmt.Printf("[%d/%d] %s %s\n", i+1, len(actions), action.Item, action.Path)
+
+		sourcePath := filepath.Join(source, filepath.FromSlash(action.Path))
+		destinationPath := filepath.Join(destination, filepath.FromSlash(action.Path))
+		switch action.Kind {
+		case "delete":
+			err = os.RemoveAll(destinationPath)
+		case "mkdir":
+			err = mirrorDirectory(sourcePath, destinationPath)
+		case "symlink":
+			err = mirrorSymlink(sourcePath, destinationPath)
+		case "copy":
+			err = mirrorFile(sourcePath, destinationPath)
+			transferred += action.Size
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s %s: %w", action.Kind, action.Path, err)
+		}
+	}
+
+	// Restore directory times last, copying files into them changes their mtime
+	filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(source, path)
+		rel = filepath.ToSlash(rel)
+		if rel != "." && MatchRsyncExclude(rel, true, options.Excludes) {
+			return filepath.SkipDir
+		}
+		os.Chtimes(filepath.Join(destination, filepath.FromSlash(rel)), info.ModTime(), info.ModTime())
+		return nil
+	})
+
+	fmt.Printf("✅ Successfully mirror %s to %s (%d changes, %d bytes transferred)\n", source, destination, len(actions), transferred)
+	return nil
+}
+
+// Plan the changes required to mirror source into destination
+func PlanMirror(source string, destination string, options MirrorOptions) ([]MirrorAction, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("source is not a directory: %s", source)
+	}
+
+	var actions []MirrorAction
+	var deletions []MirrorAction
+	sourceEntries := make(map[string]bool)
+
+	// Create the destination root on a first mirror, like rsync's "./"
+	if _, err := os.Stat(destination); os.IsNotExist(err) {
+		actions = append(actions, MirrorAction{Item: "cd+++++++++", Path: "./", Kind: "mkdir"})
+	}
+
+	err = filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == source {
+			return nil
+		}
+
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		// Check if the path should be ignored
+		if MatchRsyncExclude(rel, info.IsDir(), options.Excludes) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		sourceEntries[rel] = true
+
+		destinationPath := filepath.Join(destination, filepath.FromSlash(rel))
+		destinationInfo, destinationErr := os.Lstat(destinationPath)
+		exists := destinationErr == nil
+
+		// Replace entries that changed type
+		if exists && info.Mode().Type() != destinationInfo.Mode().Type() {
+			deletions = append(deletions, MirrorAction{Item: "*deleting  ", Path: rel, Kind: "delete"})
+			exists = false
+		}
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if exists {
+				current, err := os.Readlink(destinationPath)
+				if err == nil && current == target {
+					return nil
+				}
+			}
+			item := "cL+++++++++"
+			if exists {
+				item = "cL........."
+			}
+			actions = append(actions, MirrorAction{Item: item, Path: rel, Kind: "symlink"})
+		case info.IsDir():
+			if !exists {
+				actions = append(actions, MirrorAction{Item: "cd+++++++++", Path: rel + "/", Kind: "mkdir"})
+			} else if destinationInfo.Mode().Perm() != info.Mode().Perm() {
+				actions = append(actions, MirrorAction{Item: ".d...p.....", Path: rel + "/", Kind: "mkdir"})
+			}
+		case info.Mode().IsRegular():
+			if !exists {
+				actions = append(actions, MirrorAction{Item: ">f+++++++++", Path: rel, Kind: "copy", Size: info.Size()})
+				return nil
+			}
+			changes, err := mirrorChanges(path, destinationPath, info, destinationInfo, options.Checksum)
+			if err != nil {
+				return err
+			}
+			if changes != "" {
+				actions = append(actions, MirrorAction{Item: ">f" + changes, Path: rel, Kind: "copy", Size: info.Size()})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Find extraneous files in destination
+	if options.Delete {
+		err = filepath.Walk(destination, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if path == destination {
+				return nil
+			}
+
+			rel, err := filepath.Rel(destination, path)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+
+			// Excluded files are protected from deletion, like rsync
+			if MatchRsyncExclude(rel, info.IsDir(), options.Excludes) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !sourceEntries[rel] {
+				deletions = append(deletions, MirrorAction{Item: "*deleting  ", Path: rel, Kind: "delete"})
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(deletions, func(i, j int) bool {
+		return deletions[i].Path > deletions[j].Path
+	})
+
+	return append(deletions, actions...), nil
+}
+
+// MatchRsyncExclude checks a slash separated relative path against rsync style exclude patterns.
+// Patterns without a slash match any path component, a leading slash anchors to the root,
+// and a trailing slash only matches directories.
+func MatchRsyncExclude(rel string, isDir bool, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if strings.HasSuffix(pattern, "/") {
+			if !isDir {
+				continue
+			}
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+
+		if strings.HasPrefix(pattern, "/") {
+			if ok, _ := filepath.Match(strings.TrimPrefix(pattern, "/"), rel); ok {
+				return true
+			}
+			continue
+		}
+		if strings.Contains(pattern, "/") {
+			// Match against the trailing components of the path
+			parts := strings.Split(rel, "/")
+			for i := range parts {
+				if ok, _ := filepath.Match(pattern, strings.Join(parts[i:], "/")); ok {
+					return true
+				}
+			}
+			continue
+		}
+		if ok, _ := filepath.Match(pattern, filepath.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare a source and destination file, returning rsync style change flags or empty when equal
+func mirrorChanges(sourcePath string, destinationPath string, sourceInfo os.FileInfo, destinationInfo os.FileInfo, checksum bool) (string, error) {
+	flags := []byte(".........")
+	changed := false
+
+	if checksum {
+		sourceHash, err := HashFile(sourcePath)
+		if err != nil {
+			return "", err
+		}
+		destinationHash, err := HashFile(destinationPath)
+		if err != nil {
+			return "", err
+		}
+		if sourceHash != destinationHash {
+			flags[0] = 'c'
+			changed = true
+		}
+	} else {
+		if sourceInfo.Size() != destinationInfo.Size() {
+			flags[1] = 's'
+			changed = true
+		}
+		if !sourceInfo.ModTime().Equal(destinationInfo.ModTime()) {
+			flags[2] = 't'
+			changed = true
+		}
+	}
+	if sourceInfo.Mode().Perm() != destinationInfo.Mode().Perm() {
+		flags[3] = 'p'
+		changed = true
+	}
+
+	if !changed {
+		return "", nil
+	}
+	return string(flags), nil
+}
+
+// Create a directory with the source mode
+func mirrorDirectory(sourcePath string, destinationPath string) error {
+	info, err := os.Stat(sourcePath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(destinationPath, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chmod(destinationPath, info.Mode().Perm())
+}
+
+// Recreate a symlink pointing to the same target
+func mirrorSymlink(sourcePath string, destinationPath string) error {
+	target, err := os.Readlink(sourcePath)
+	if err != nil {
+		return err
+	}
+	os.Remove(destinationPath)
+	return os.Symlink(target, destinationPath)
+}
+
+// Copy a file through a temporary file, preserving mode and times
+func mirrorFile(sourcePath string, destinationPath string) error {
+	info, err := os.Stat(sourcePath)
+	if err != nil {
+		return err
+	}
+
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	temp, err := os.CreateTemp(filepath.Dir(destinationPath), ".aspri-mirror-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := io.Copy(temp, source); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(temp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := os.Chtimes(temp.Name(), info.ModTime(), info.ModTime()); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), destinationPath)
+}
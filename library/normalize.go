@@ -0,0 +1,233 @@
+package library
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Normalize Options
+type NormalizeOptions struct {
+	EOL          string // lf or crlf, empty keeps line endings
+	StripBOM     bool
+	TrimTrailing bool
+	FinalNewline bool
+	ToUTF8       bool
+	Encoding     string // Legacy encoding assumed for invalid UTF-8 files
+}
+
+// Normalize Result
+type NormalizeResult struct {
+	Path   string   `json:"path"`
+	Issues []string `json:"issues"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// Byte order mark for UTF-8
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Legacy encodings supported when converting to UTF-8
+func legacyEncoding(name string) (encoding.Encoding, error) {
+	switch strings.ToLower(strings.ReplaceAll(name, "_", "-")) {
+	case "", "windows-1252", "cp1252":
+		return charmap.Windows1252, nil
+	case "latin1", "latin-1", "iso-8859-1":
+		return charmap.ISO8859_1, nil
+	case "latin9", "latin-9", "iso-8859-15":
+		return charmap.ISO8859_15, nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %s", name)
+	}
+}
+
+// Detect line ending style of content: lf, crlf, cr, mixed or none
+func DetectLineEndings(content []byte) string {
+	crlf := bytes.Count(content, []byte("\r\n"))
+	lf := bytes.Count(content, []byte("\n")) - crlf
+	cr := bytes.Count(content, []byte("\r")) - crlf
+
+	styles := 0
+	style := "none"
+	if lf > 0 {
+		styles++
+		style = "lf"
+	}
+	if crlf > 0 {
+		styles++
+		style = "crlf"
+	}
+	if cr > 0 {
+		styles++
+		style = "cr"
+	}
+	if styles > 1 {
+		return "mixed"
+	}
+	return style
+}
+
+// Normalize content, returning the new content and the list of issues found
+func NormalizeContent(content []byte, options NormalizeOptions) ([]byte, []string, error) {
+	var issues []string
+
+	// Byte order mark
+	if options.StripBOM && bytes.HasPrefix(content, utf8BOM) {
+		content = content[len(utf8BOM):]
+		issues = append(issues, "utf-8 bom")
+	}
+
+	// Encoding
+	if options.ToUTF8 && !utf8.Valid(content) {
+		legacy, err := legacyEncoding(options.Encoding)
+		if err != nil {
+			return nil, nil, err
+		}
+		decoded, err := legacy.NewDecoder().Bytes(content)
+		if err != nil {
+			return nil, nil, err
+		}
+		content = decoded
+		issues = append(issues, "not utf-8")
+	}
+
+	// Work on LF internally
+	lineEndings := DetectLineEndings(content)
+	text := string(content)
+	if options.EOL != "" {
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+		text = strings.ReplaceAll(text, "\r", "\n")
+		if lineEndings != "none" && lineEndings != options.EOL {
+			issues = append(issues, lineEndings+" line endings")
+		}
+	}
+
+	// Trailing whitespace
+	if options.TrimTrailing {
+		lines := strings.Split(text, "\n")
+		trimmed := false
+		for i, line := range lines {
+			// Keep a CR that belongs to a CRLF line ending
+			cr := ""
+			if options.EOL == "" && strings.HasSuffix(line, "\r") {
+				cr = "\r"
+				line = strings.TrimSuffix(line, "\r")
+			}
+			clean := strings.TrimRight(line, " \t")
+			if clean != line {
+				trimmed = true
+			}
+			lines[i] = clean + cr
+		}
+		if trimmed {
+			issues = append(issues, "trailing whitespace")
+		}
+		text = strings.Join(lines, "\n")
+	}
+
+	// Final newline
+	if options.FinalNewline && text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+		issues = append(issues, "missing final newline")
+	}
+
+	if options.EOL == "crlf" {
+		text = strings.ReplaceAll(text, "\n", "\r\n")
+	}
+
+	return []byte(text), issues, nil
+}
+
+// Normalize text files under root, in check mode files are only reported
+func NormalizeFiles(root string, filter FileFilter, options NormalizeOptions, check bool, workers int) ([]NormalizeResult, error) {
+	if options.EOL != "" && options.EOL != "lf" && options.EOL != "crlf" {
+		return nil, fmt.Errorf("unsupported line ending: %s (lf|crlf)", options.EOL)
+	}
+	if _, err := legacyEncoding(options.Encoding); options.ToUTF8 && err != nil {
+		return nil, err
+	}
+
+	files, err := filter.CollectFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]NormalizeResult, len(files))
+	ParallelScan(files, workers, func(i int, path string) {
+		results[i] = NormalizeFile(path, options, check)
+	})
+
+	var changed []NormalizeResult
+	for _, result := range results {
+		if len(result.Issues) > 0 || result.Error != "" {
+			changed = append(changed, result)
+		}
+	}
+	return changed, nil
+}
+
+// Normalize a single file, binary files are skipped
+func NormalizeFile(path string, options NormalizeOptions, check bool) NormalizeResult {
+	result := NormalizeResult{Path: path}
+	if IsBinaryFile(path) {
+		return result
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	normalized, issues, err := NormalizeContent(content, options)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Issues = issues
+
+	if !check && !bytes.Equal(content, normalized) {
+		if err := os.WriteFile(path, normalized, info.Mode().Perm()); err != nil {
+			result.Error = err.Error()
+		}
+	}
+	return result
+}
+
+// Print Normalize Results
+func PrintNormalizeResults(results []NormalizeResult, format string, check bool) {
+	if format == "json" {
+		if results == nil {
+			results = []NormalizeResult{}
+		}
+		PrintJSON(results)
+		return
+	}
+
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Println("❌", result.Path, result.Error)
+			continue
+		}
+		if check {
+			fmt.Printf("⚠️ %s: %s\n", result.Path, strings.Join(result.Issues, ", "))
+		} else {
+			fmt.Printf("✅ Normalized %s: %s\n", result.Path, strings.Join(result.Issues, ", "))
+		}
+	}
+
+	if check && len(results) > 0 {
+		fmt.Println("❌", len(results), "files need normalization")
+	} else if check {
+		fmt.Println("✅ All files are normalized")
+	}
+}
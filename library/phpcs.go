@@ -41,6 +41,12 @@ func phpCSGetConfig() (PHPCSConfig, error) {
 		return PHPCSConfig{}, err
 	}
 
+	// Validate against the PHPCS config schema.
+	if err := ValidateConfigFile(configPath, "phpcs-config"); err != nil {
+		fmt.Println("❌", err)
+		return PHPCSConfig{}, err
+	}
+
 	// Parse the JSON data into a Config struct.
 	var config PHPCSConfig
 	err = json.Unmarshal(configData, &config)
@@ -72,6 +78,11 @@ func phpCSDetectStandard() ([]string, error) {
 		return standards, err
 	}
 
+	// Validate against the PHPCS standards schema
+	if err := ValidateConfigFile(standardsJSON, "phpcs-standards"); err != nil {
+		return standards, err
+	}
+
 	// Unmarshal standards.json data into slice of strings
 	err = json.Unmarshal(bytes, &standardsDirectory)
 	if err != nil {
@@ -20,28 +20,62 @@ type RsyncDirectory struct {
 
 // Initiate File Function
 func InitiateRsyncFunction(flags Flag) {
-	/** Minify Files in Path .js and .css */
+	/** Native local mirror based on rsync.json */
+	if *flags.Rsync && *flags.Mirror {
+		config, err := readRsyncConfig("rsync.json")
+		if err != nil {
+			fmt.Println("❌", err)
+			return
+		}
+		options := MirrorOptions{
+			Delete:   *flags.Delete,
+			Checksum: *flags.Checksum,
+			DryRun:   *flags.DryRun,
+			Excludes: append(config.Excludes, *flags.Exclude...),
+		}
+		if err := MirrorFromConfig(config, options); err != nil {
+			fmt.Println("❌ Error mirroring:", err)
+		}
+		return
+	}
+	/** Generate rsync script */
 	if *flags.Rsync {
 		Rsync()
 	}
 }
 
-// Rsync command
-func Rsync() {
+// Read and parse rsync.json
+func readRsyncConfig(path string) (RsyncConfig, error) {
+	var config RsyncConfig
+
+	// Validate against the rsync schema, typos would otherwise leave fields empty
+	if _, err := os.Stat(path); err == nil {
+		if err := ValidateConfigFile(path, "rsync"); err != nil {
+			return config, err
+		}
+	}
+
 	// Read the JSON file
-	file, err := os.Open("rsync.json")
+	file, err := os.Open(path)
 	if err != nil {
-		fmt.Println("❌ Error opening JSON file:", err)
-		return
+		return config, fmt.Errorf("error opening JSON file: %w", err)
 	}
 	defer file.Close()
 
 	// Parse the JSON data into a RsyncConfig struct
-	var config RsyncConfig
 	decoder := json.NewDecoder(file)
-	err = decoder.Decode(&config)
+	if err := decoder.Decode(&config); err != nil {
+		return config, fmt.Errorf("error decoding JSON: %w", err)
+	}
+
+	return config, nil
+}
+
+// Rsync command
+func Rsync() {
+	config, err := readRsyncConfig("rsync.json")
 	if err != nil {
-		fmt.Println("❌ Error decoding JSON:", err)
+		fmt.Println("❌", err)
 		return
 	}
 
@@ -0,0 +1,94 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// File Filter shared by the walking functions
+type FileFilter struct {
+	Exclude []string // Skip paths containing any of these
+	Ext     []string // Only include files with these extensions (empty means all)
+	Name    string   // Only include files whose name contains this text
+}
+
+// Create a File Filter from the common flags (--exclude, --ext, --regex)
+func NewFileFilter(flags Flag) FileFilter {
+	return FileFilter{
+		Exclude: *flags.Exclude,
+		Ext:     *flags.Ext,
+		Name:    *flags.Regex,
+	}
+}
+
+// Check if the path should be ignored
+func (f FileFilter) Excluded(path string) bool {
+	for _, excludePath := range f.Exclude {
+		if excludePath != "" && strings.Contains(path, excludePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if a file passes the filter
+func (f FileFilter) Match(path string, info os.FileInfo) bool {
+	if info.IsDir() || f.Excluded(path) {
+		return false
+	}
+	if len(f.Ext) > 0 && !SliceContainsString(f.Ext, strings.ToLower(filepath.Ext(path))) {
+		return false
+	}
+	return f.Name == "" || strings.Contains(info.Name(), f.Name)
+}
+
+// Collect all regular files under root that pass the filter
+func (f FileFilter) CollectFiles(root string) ([]string, error) {
+	if root == "" {
+		CurrentDirectory, _ := os.Getwd()
+		root = CurrentDirectory
+	}
+
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && path != root && f.Excluded(path) {
+			return filepath.SkipDir
+		}
+		if info.Mode().IsRegular() && f.Match(path, info) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// Run fn for every file using a pool of workers, workers <= 0 uses the number of CPUs.
+// fn receives the index of the file so callers can store results in order.
+func ParallelScan(files []string, workers int, fn func(index int, path string)) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i, files[i])
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
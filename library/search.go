@@ -0,0 +1,211 @@
+package library
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Search Options
+type SearchOptions struct {
+	Patterns     []string
+	FixedStrings bool
+	IgnoreCase   bool
+	Word         bool
+	Context      int
+}
+
+// Search Match
+type SearchMatch struct {
+	Line   int      `json:"line"`
+	Text   string   `json:"text"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+}
+
+// Search Result of a single file
+type SearchResult struct {
+	Path    string        `json:"path"`
+	Count   int           `json:"count"`
+	Matches []SearchMatch `json:"matches,omitempty"`
+	lines   []string
+}
+
+// Compile all patterns into a single regular expression
+func CompileSearchPattern(options SearchOptions) (*regexp.Regexp, error) {
+	if len(options.Patterns) == 0 {
+		return nil, fmt.Errorf("no search pattern provided")
+	}
+
+	var parts []string
+	for _, pattern := range options.Patterns {
+		if options.FixedStrings {
+			pattern = regexp.QuoteMeta(pattern)
+		}
+		parts = append(parts, "(?:"+pattern+")")
+	}
+
+	expression := strings.Join(parts, "|")
+	if options.Word {
+		expression = `\b(?:` + expression + `)\b`
+	}
+	if options.IgnoreCase {
+		expression = "(?i)" + expression
+	}
+	return regexp.Compile(expression)
+}
+
+// Search files under root in parallel, binary files are skipped
+func SearchFiles(root string, filter FileFilter, options SearchOptions, workers int) ([]SearchResult, error) {
+	re, err := CompileSearchPattern(options)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filter.CollectFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, len(files))
+	ParallelScan(files, workers, func(i int, path string) {
+		results[i] = SearchFile(path, re, options.Context)
+	})
+
+	return results, nil
+}
+
+// Search a single file
+func SearchFile(path string, re *regexp.Regexp, context int) SearchResult {
+	result := SearchResult{Path: path}
+	if IsBinaryFile(path) {
+		return result
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Println("❌ Error:", err)
+		return result
+	}
+
+	lines := splitLines(strings.ReplaceAll(string(content), "\r\n", "\n"))
+	for i, line := range lines {
+		if !re.MatchString(line) {
+			continue
+		}
+
+		match := SearchMatch{Line: i + 1, Text: line}
+		if context > 0 {
+			start := i - context
+			if start < 0 {
+				start = 0
+			}
+			end := i + context + 1
+			if end > len(lines) {
+				end = len(lines)
+			}
+			match.Before = lines[start:i]
+			match.After = lines[i+1 : end]
+		}
+		result.Matches = append(result.Matches, match)
+	}
+	result.Count = len(result.Matches)
+	if result.Count > 0 {
+		result.lines = lines
+	}
+
+	return result
+}
+
+// Print search results in grep style, as counts, as files without match or as JSON
+func PrintSearchResults(results []SearchResult, format string, count bool, filesWithoutMatch bool, context int) {
+	if filesWithoutMatch {
+		var paths []string
+		for _, result := range results {
+			if result.Count == 0 {
+				paths = append(paths, result.Path)
+			}
+		}
+		if format == "json" {
+			if paths == nil {
+				paths = []string{}
+			}
+			PrintJSON(paths)
+			return
+		}
+		for _, path := range paths {
+			fmt.Println(path)
+		}
+		return
+	}
+
+	var matched []SearchResult
+	var total int
+	for _, result := range results {
+		if result.Count > 0 {
+			matched = append(matched, result)
+			total += result.Count
+		}
+	}
+
+	if format == "json" {
+		if matched == nil {
+			matched = []SearchResult{}
+		}
+		if count {
+			for i := range matched {
+				matched[i].Matches = nil
+			}
+		}
+		PrintJSON(matched)
+		return
+	}
+
+	if count {
+		for _, result := range matched {
+			fmt.Printf("%s:%d\n", result.Path, result.Count)
+		}
+	} else {
+		for _, result := range matched {
+			printSearchResult(result, context)
+		}
+	}
+	fmt.Println("🐙 There are", total, "matches in", len(matched), "files")
+}
+
+// Print a single file's matches, merging overlapping context like grep
+func printSearchResult(result SearchResult, context int) {
+	matchLines := make(map[int]bool)
+	for _, match := range result.Matches {
+		matchLines[match.Line-1] = true
+	}
+
+	last := -1
+	for _, match := range result.Matches {
+		start := match.Line - 1 - context
+		if start < 0 {
+			start = 0
+		}
+		if start <= last {
+			start = last + 1
+		}
+		end := match.Line - 1 + context
+		if end >= len(result.lines) {
+			end = len(result.lines) - 1
+		}
+		if context > 0 && last >= 0 && start > last+1 {
+			fmt.Println("--")
+		}
+		for i := start; i <= end; i++ {
+			separator := "-"
+			if matchLines[i] {
+				separator = ":"
+			}
+			fmt.Printf("%s%s%d%s%s\n", result.Path, separator, i+1, separator, result.lines[i])
+		}
+		if end > last {
+			last = end
+		}
+	}
+}
@@ -0,0 +1,491 @@
+package library
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sitemap URL entry
+type SitemapURL struct {
+	Loc        string `xml:"loc" json:"loc"`
+	Lastmod    string `xml:"lastmod,omitempty" json:"lastmod,omitempty"`
+	Changefreq string `xml:"changefreq,omitempty" json:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty" json:"priority,omitempty"`
+	Sitemap    string `xml:"-" json:"sitemap,omitempty"` // Sitemap the entry was read from
+}
+
+// Sitemap document, either a urlset or a sitemapindex
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []SitemapURL `xml:"url"`
+	Sitemaps []SitemapURL `xml:"sitemap"`
+}
+
+// Sitemap Difference of a URL
+type SitemapDifference struct {
+	Loc    string `json:"loc"`
+	Status string `json:"status"` // added, removed or changed
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+// Sitemap protocol limits and namespace
+const (
+	SitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	SitemapMaxURLs   = 50000
+)
+
+// Sitemap file names tried in a directory
+var sitemapIndexNames = []string{"sitemap_index.xml", "sitemap-index.xml", "sitemap.xml", "sitemap_index.xml.gz", "sitemap.xml.gz"}
+
+/** Initiate Sitemap Function */
+func InitiateSitemapFunction(flags Flag) {
+	if !*flags.Sitemap {
+		return
+	}
+
+	/** Generate a sitemap from a URL list or a static site directory */
+	if *flags.Generate {
+		var urls []SitemapURL
+		var err error
+		if *flags.Input != "" {
+			urls, err = SitemapURLsFromList(*flags.Input)
+		} else {
+			urls, err = SitemapURLsFromDirectory(*flags.Path, *flags.Url, *flags.Exclude)
+		}
+		if err != nil {
+			fmt.Println("❌", err)
+			os.Exit(1)
+		}
+		files, err := WriteSitemap(urls, *flags.Output, *flags.Url)
+		if err != nil {
+			fmt.Println("❌ Error writing sitemap:", err)
+			os.Exit(1)
+		}
+		for _, file := range files {
+			fmt.Println("✅ Written", file)
+		}
+		if len(files) > 0 {
+			fmt.Println("🐙 There are", len(urls), "URLs")
+		}
+		return
+	}
+
+	/** Diff two sitemaps */
+	if *flags.Diff && len(*flags.ComparePaths) > 0 {
+		before, warnings := CollectSitemap(*flags.Path)
+		after, moreWarnings := CollectSitemap((*flags.ComparePaths)[0])
+		for _, warning := range append(warnings, moreWarnings...) {
+			fmt.Fprintln(os.Stderr, "⚠️", warning)
+		}
+		PrintSitemapDifferences(DiffSitemaps(before, after), *flags.Format)
+		return
+	}
+
+	/** List the URLs of a sitemap, child sitemaps are followed */
+	urls, warnings := CollectSitemap(*flags.Path)
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "⚠️", warning)
+	}
+	PrintSitemapURLs(urls, *flags.Format)
+}
+
+// Read a sitemap file or URL, gzip compressed sitemaps are decompressed.
+// Returns the URLs of a urlset or the child sitemaps of a sitemapindex.
+func ReadSitemap(source string) ([]SitemapURL, []SitemapURL, error) {
+	var content []byte
+	var err error
+	if isValidURL(source) {
+		content, err = fetchSitemap(source)
+	} else {
+		content, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Gzip magic number, servers don't always set the extension
+	if bytes.HasPrefix(content, []byte{0x1f, 0x8b}) {
+		reader, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", source, err)
+		}
+		content, err = io.ReadAll(reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", source, err)
+		}
+	}
+
+	var document sitemapDocument
+	if err := xml.Unmarshal(content, &document); err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", source, err)
+	}
+	switch document.XMLName.Local {
+	case "urlset":
+		for i := range document.URLs {
+			document.URLs[i] = trimSitemapURL(document.URLs[i], source)
+		}
+		return document.URLs, nil, nil
+	case "sitemapindex":
+		for i := range document.Sitemaps {
+			document.Sitemaps[i] = trimSitemapURL(document.Sitemaps[i], source)
+		}
+		return nil, document.Sitemaps, nil
+	}
+	return nil, nil, fmt.Errorf("%s: not a sitemap, root element is <%s>", source, document.XMLName.Local)
+}
+
+// Fetch a remote sitemap
+func fetchSitemap(source string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	response, err := client.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", source, response.Status)
+	}
+	return io.ReadAll(response.Body)
+}
+
+func trimSitemapURL(entry SitemapURL, source string) SitemapURL {
+	entry.Loc = strings.TrimSpace(entry.Loc)
+	entry.Lastmod = strings.TrimSpace(entry.Lastmod)
+	entry.Changefreq = strings.TrimSpace(entry.Changefreq)
+	entry.Priority = strings.TrimSpace(entry.Priority)
+	entry.Sitemap = source
+	return entry
+}
+
+// Collect the URLs of a sitemap file, directory or URL, following child sitemaps once each.
+// Child sitemaps of a local sitemap are read from its directory when a file with the same name exists.
+func CollectSitemap(source string) ([]SitemapURL, []string) {
+	var urls []SitemapURL
+	var warnings []string
+
+	queue := []string{source}
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		queue = sitemapDirectoryFiles(source)
+		if len(queue) == 0 {
+			return nil, []string{"no sitemap in " + source}
+		}
+	}
+
+	visited := make(map[string]bool)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		entries, children, err := ReadSitemap(current)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		urls = append(urls, entries...)
+		for _, child := range children {
+			queue = append(queue, resolveSitemapChild(current, child.Loc))
+		}
+	}
+	return urls, warnings
+}
+
+// Sitemap files of a directory: the index when there is one, otherwise every .xml and .xml.gz file
+func sitemapDirectoryFiles(dir string) []string {
+	for _, name := range sitemapIndexNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return []string{filepath.Join(dir, name)}
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name())
+		if !entry.IsDir() && (strings.HasSuffix(name, ".xml") || strings.HasSuffix(name, ".xml.gz")) {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return files
+}
+
+// Location of a child sitemap, local copies next to a local parent are preferred
+func resolveSitemapChild(parent string, loc string) string {
+	if isValidURL(parent) {
+		if base, err := url.Parse(parent); err == nil {
+			if reference, err := url.Parse(loc); err == nil {
+				return base.ResolveReference(reference).String()
+			}
+		}
+		return loc
+	}
+
+	name := loc
+	if parsed, err := url.Parse(loc); err == nil && parsed.Path != "" {
+		name = path.Base(parsed.Path)
+	}
+	if local := filepath.Join(filepath.Dir(parent), name); name != "" {
+		if _, err := os.Stat(local); err == nil {
+			return local
+		}
+	}
+	return loc
+}
+
+// Compare two sitemaps by URL, lastmod and priority changes are reported
+func DiffSitemaps(before []SitemapURL, after []SitemapURL) []SitemapDifference {
+	index := func(urls []SitemapURL) map[string]SitemapURL {
+		result := make(map[string]SitemapURL, len(urls))
+		for _, entry := range urls {
+			result[entry.Loc] = entry
+		}
+		return result
+	}
+	old, updated := index(before), index(after)
+
+	var differences []SitemapDifference
+	for loc, entry := range old {
+		other, ok := updated[loc]
+		if !ok {
+			differences = append(differences, SitemapDifference{Loc: loc, Status: "removed", Old: entry.Lastmod})
+			continue
+		}
+		if entry.Lastmod != other.Lastmod || entry.Priority != other.Priority {
+			differences = append(differences, SitemapDifference{Loc: loc, Status: "changed",
+				Old: sitemapSummary(entry), New: sitemapSummary(other)})
+		}
+	}
+	for loc, entry := range updated {
+		if _, ok := old[loc]; !ok {
+			differences = append(differences, SitemapDifference{Loc: loc, Status: "added", New: entry.Lastmod})
+		}
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		if differences[i].Status != differences[j].Status {
+			return differences[i].Status < differences[j].Status
+		}
+		return differences[i].Loc < differences[j].Loc
+	})
+	return differences
+}
+
+// Lastmod and priority of an entry, e.g. "2026-10-18 0.8"
+func sitemapSummary(entry SitemapURL) string {
+	return strings.TrimSpace(entry.Lastmod + " " + entry.Priority)
+}
+
+// Read a URL list, one absolute URL per line. Empty lines and lines starting with # are skipped.
+func SitemapURLsFromList(file string) ([]SitemapURL, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var urls []SitemapURL
+	seen := make(map[string]bool)
+	for number, line := range strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !isValidURL(line) {
+			return nil, fmt.Errorf("%s:%d: not an absolute URL: %s", file, number+1, line)
+		}
+		if !seen[line] {
+			seen[line] = true
+			urls = append(urls, SitemapURL{Loc: line})
+		}
+	}
+	return urls, nil
+}
+
+// URLs of the HTML pages of a static site directory, index.html maps to its directory.
+// Lastmod is the file modification date.
+func SitemapURLsFromDirectory(dir string, baseURL string, exclude []string) ([]SitemapURL, error) {
+	if !isValidURL(baseURL) {
+		return nil, fmt.Errorf("--url must be the absolute base URL of the site, e.g. https://example.com")
+	}
+	root := dir
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	files, err := FileFilter{Exclude: exclude, Ext: []string{".html", ".htm"}}.CollectFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	base := strings.TrimSuffix(baseURL, "/")
+	var urls []SitemapURL
+	for _, file := range files {
+		rel, _ := filepath.Rel(root, file)
+		rel = filepath.ToSlash(rel)
+		if name := path.Base(rel); name == "index.html" || name == "index.htm" {
+			rel = strings.TrimSuffix(rel, name)
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		loc := base + (&url.URL{Path: "/" + rel}).EscapedPath()
+		urls = append(urls, SitemapURL{Loc: loc, Lastmod: info.ModTime().Format(dateFormat)})
+	}
+	sort.Slice(urls, func(i, j int) bool {
+		return urls[i].Loc < urls[j].Loc
+	})
+	return urls, nil
+}
+
+// Encode a urlset or a sitemapindex
+func encodeSitemap(root string, entries []SitemapURL) ([]byte, error) {
+	element := "url"
+	if root == "sitemapindex" {
+		element = "sitemap"
+	}
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buffer)
+	encoder.Indent("", "  ")
+	start := xml.StartElement{Name: xml.Name{Local: root}, Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: SitemapNamespace}}}
+	if err := encoder.EncodeToken(start); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if err := encoder.EncodeElement(entry, xml.StartElement{Name: xml.Name{Local: element}}); err != nil {
+			return nil, err
+		}
+	}
+	if err := encoder.EncodeToken(start.End()); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	buffer.WriteString("\n")
+	return buffer.Bytes(), nil
+}
+
+// Write a sitemap to the output file or stdout. Above the protocol limit of 50,000 URLs the sitemap is split
+// into numbered files next to the output and the output becomes their sitemapindex. Returns the written files.
+func WriteSitemap(urls []SitemapURL, output string, baseURL string) ([]string, error) {
+	if len(urls) <= SitemapMaxURLs {
+		content, err := encodeSitemap("urlset", urls)
+		if err != nil {
+			return nil, err
+		}
+		if output == "" || output == "-" {
+			_, err = os.Stdout.Write(content)
+			return nil, err
+		}
+		return []string{output}, writeSitemapFile(output, content)
+	}
+
+	if output == "" || output == "-" {
+		return nil, fmt.Errorf("%d URLs exceed the limit of %d per sitemap, set --output to split them", len(urls), SitemapMaxURLs)
+	}
+	if !isValidURL(baseURL) {
+		return nil, fmt.Errorf("%d URLs exceed the limit of %d per sitemap, set --url to the base URL of the split sitemaps", len(urls), SitemapMaxURLs)
+	}
+	extension := filepath.Ext(output)
+	if strings.HasSuffix(strings.ToLower(output), ".xml.gz") {
+		extension = output[len(output)-len(".xml.gz"):]
+	}
+	prefix := strings.TrimSuffix(output, extension)
+	today := time.Now().Format(dateFormat)
+
+	var files []string
+	var children []SitemapURL
+	for start := 0; start < len(urls); start += SitemapMaxURLs {
+		end := start + SitemapMaxURLs
+		if end > len(urls) {
+			end = len(urls)
+		}
+		file := fmt.Sprintf("%s-%d%s", prefix, len(files)+1, extension)
+		content, err := encodeSitemap("urlset", urls[start:end])
+		if err != nil {
+			return files, err
+		}
+		if err := writeSitemapFile(file, content); err != nil {
+			return files, err
+		}
+		files = append(files, file)
+		children = append(children, SitemapURL{Loc: strings.TrimSuffix(baseURL, "/") + "/" + filepath.Base(file), Lastmod: today})
+	}
+
+	content, err := encodeSitemap("sitemapindex", children)
+	if err != nil {
+		return files, err
+	}
+	return append(files, output), writeSitemapFile(output, content)
+}
+
+// Write a sitemap file, gzip compressed when the name ends in .gz
+func writeSitemapFile(path string, content []byte) error {
+	if strings.HasSuffix(strings.ToLower(path), ".gz") {
+		var buffer bytes.Buffer
+		writer := gzip.NewWriter(&buffer)
+		if _, err := writer.Write(content); err != nil {
+			return err
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		content = buffer.Bytes()
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// Print sitemap URLs as table, json or a plain list
+func PrintSitemapURLs(urls []SitemapURL, format string) {
+	switch format {
+	case "json":
+		if urls == nil {
+			urls = []SitemapURL{}
+		}
+		PrintJSON(urls)
+	case "list":
+		for _, entry := range urls {
+			fmt.Println(entry.Loc)
+		}
+	default:
+		var rows [][]string
+		for _, entry := range urls {
+			rows = append(rows, []string{entry.Loc, entry.Lastmod, entry.Priority})
+		}
+		PrintTable([]string{"URL", "LASTMOD", "PRIORITY"}, rows)
+		fmt.Println("🐙 There are", len(urls), "URLs")
+	}
+}
+
+// Print sitemap differences as table or json
+func PrintSitemapDifferences(differences []SitemapDifference, format string) {
+	if format == "json" {
+		if differences == nil {
+			differences = []SitemapDifference{}
+		}
+		PrintJSON(differences)
+		return
+	}
+	var rows [][]string
+	for _, difference := range differences {
+		rows = append(rows, []string{difference.Status, difference.Loc, difference.Old, difference.New})
+	}
+	PrintTable([]string{"STATUS", "URL", "OLD", "NEW"}, rows)
+	fmt.Println("🐙 There are", len(differences), "differences")
+}
@@ -3,121 +3,288 @@ package library
 import (
 	"bufio"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
+)
+
+// YouTube Video
+type YouTubeVideo struct {
+	Title         string   `json:"title"`
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Views         int64    `json:"views"`
+	Published     string   `json:"published,omitempty"` // Approximate absolute date of the relative publish text
+	PublishedText string   `json:"published_text,omitempty"`
+	Length        int      `json:"length"` // Seconds
+	LengthText    string   `json:"length_text,omitempty"`
+	Thumbnails    []string `json:"thumbnails,omitempty"` // Smallest first
+}
+
+// YouTube Parse Failure of an input line, or of a video renderer of a saved page
+type YouTubeFailure struct {
+	Line   int    `json:"line"` // Line number, or renderer number of a saved page
+	Reason string `json:"reason"`
+	Text   string `json:"text"`
+}
+
+// Patterns of a copied video line: "Title 1.2K views 3 weeks ago 12 minutes, 5 seconds https://www.youtube.com/watch?v=ID"
+var (
+	youtubeViewsPattern    = regexp.MustCompile(`(?i)\b(no|[\d.,]+\s*[KMB]?)\s+views?\b`)
+	youtubeReleasePattern  = regexp.MustCompile(`(?i)\b(\d+)\s+(second|minute|hour|day|week|month|year)s?\s+ago\b`)
+	youtubeDurationPattern = regexp.MustCompile(`(?i)\b(\d+)\s+(hour|minute|second)s?\b,?`)
+	youtubeClockPattern    = regexp.MustCompile(`\b(?:(\d+):)?(\d{1,2}):(\d{2})\b`)
+	youtubeIDPattern       = regexp.MustCompile(`(?:youtube\.com/(?:watch\?(?:[^\s]*&)?v=|shorts/|embed/)|youtu\.be/)([\w-]{11})`)
+	youtubeURLPattern      = regexp.MustCompile(`https?://\S+`)
 )
 
 /** Initiate Syncthing Function */
 func InitiateYouTubeFunction(flags Flag) {
 	/** remove Sync Conflict Files older Than x days */
 	if *flags.YouTube && *flags.Extract && *flags.Path != "" {
-		ExtractYouTubeData(*flags.Path)
+		reference := time.Now()
+		if info, err := os.Stat(*flags.Path); err == nil {
+			reference = info.ModTime() // When the page was copied
+		}
+		reference = parseDateOr(*flags.Reference, reference)
+
+		// Saved channel or playlist pages, otherwise copied text lines
+		extract := ExtractYouTubeData
+		if extension := strings.ToLower(filepath.Ext(*flags.Path)); extension == ".html" || extension == ".htm" {
+			extract = ExtractYouTubeHTML
+		}
+		videos, failures, err := extract(*flags.Path, reference)
+		if err != nil {
+			fmt.Println("❌ Error reading file:", err)
+			os.Exit(1)
+		}
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "⚠️ Line %d: %s: %s\n", failure.Line, failure.Reason, failure.Text)
+		}
+
+		output := *flags.Output
+		if output == "" {
+			output = "output.csv"
+			if *flags.Format == "json" {
+				output = "output.json"
+			}
+		}
+		if err := WriteYouTubeVideos(videos, *flags.Format, output); err != nil {
+			fmt.Println("❌ Error writing output:", err)
+			os.Exit(1)
+		}
+		if output != "-" {
+			fmt.Println("✅", len(videos), "videos written to", output)
+		}
+		if len(failures) > 0 {
+			fmt.Fprintln(os.Stderr, "⚠️", len(failures), "lines failed to parse")
+		}
 	}
 }
 
 /** Extract YouTube Data */
-func ExtractYouTubeData(inputFilePath string) {
-	// Provide the output file path
-	outputFilePath := "output.csv"
-
+func ExtractYouTubeData(inputFilePath string, reference time.Time) ([]YouTubeVideo, []YouTubeFailure, error) {
 	// Read the data from the input file
 	file, err := os.Open(inputFilePath)
 	if err != nil {
-		fmt.Println("Error opening file:", err)
-		return
+		return nil, nil, err
 	}
 	defer file.Close()
 
-	// Create the output CSV file
-	outputFile, err := os.Create(outputFilePath)
-	if err != nil {
-		fmt.Println("Error creating output file:", err)
-		return
-	}
-	defer outputFile.Close()
-
-	writer := csv.NewWriter(outputFile)
-	defer writer.Flush()
+	var videos []YouTubeVideo
+	var failures []YouTubeFailure
 
 	// Process each line of the input file
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	number := 0
 	for scanner.Scan() {
-		line := scanner.Text()
-		line = strings.TrimLeft(line, " ")
-		line = strings.Join(strings.Fields(line), " ")
-
-		// Get the view count
-		re := regexp.MustCompile(`\b([\d,]+) views\b`)
-		match := re.FindStringSubmatch(line)
-		views := ""
-		viewsRaw := ""
-		if len(match) > 1 {
-			views = strings.Replace(match[1], ",", "", -1)
-			viewsRaw = match[1]
+		number++
+		line := strings.Join(strings.Fields(scanner.Text()), " ")
+		if line == "" {
+			continue
 		}
 
-		// Get the title
-		re = regexp.MustCompile(`^(.*?) views`)
-		match = re.FindStringSubmatch(line)
-		title := ""
-		if len(match) > 1 {
-			title = strings.Replace(match[1], viewsRaw, "", -1)
+		video, err := ParseYouTubeLine(line, reference)
+		if err != nil {
+			failures = append(failures, YouTubeFailure{Line: number, Reason: err.Error(), Text: line})
+			continue
 		}
+		videos = append(videos, video)
+	}
+	return videos, failures, scanner.Err()
+}
 
-		// Get the release date
-		re = regexp.MustCompile(`(\d+ [a-zA-Z]+ ago)`)
-		match = re.FindStringSubmatch(line)
-		release := ""
-		if len(match) > 1 {
-			release = match[1]
-		}
+// Parse a copied video line, the video link and the view count are required
+func ParseYouTubeLine(line string, reference time.Time) (YouTubeVideo, error) {
+	var video YouTubeVideo
 
-		// Get the length
-		re = regexp.MustCompile(`(\d+ [a-zA-Z]+ https)`)
-		match = re.FindStringSubmatch(line)
-		length := ""
-		if len(match) > 1 {
-			length = strings.Replace(match[1], "https", "", -1)
-		}
+	// Get the link and video id
+	id := youtubeIDPattern.FindStringSubmatch(line)
+	if id == nil {
+		return video, fmt.Errorf("no video link")
+	}
+	video.ID = id[1]
+	video.URL = "https://www.youtube.com/watch?v=" + video.ID
+	text := youtubeURLPattern.ReplaceAllString(line, "")
 
-		// Get the link
-		re = regexp.MustCompile(`\bhttps://www.youtube.com/watch\?v=([\w-]+)\b`)
-		match = re.FindStringSubmatch(line)
-		link := ""
-		if len(match) > 1 {
-			link = "https://www.youtube.com/watch?v=" + match[1]
-		}
+	// Get the view count, the title is everything before it
+	views := youtubeViewsPattern.FindStringSubmatchIndex(text)
+	if views == nil {
+		return video, fmt.Errorf("no view count")
+	}
+	count, err := ParseYouTubeViews(text[views[2]:views[3]])
+	if err != nil {
+		return video, err
+	}
+	video.Views = count
+	video.Title = strings.TrimSpace(text[:views[0]])
+	rest := text[views[1]:]
 
-		// Debug
-		//fmt.Println(title)
-		//fmt.Println(views)
-		//fmt.Println(viewsRaw)
-		//fmt.Println(release)
-		//fmt.Println(length)
-		//fmt.Println(link)
-
-		// Transform the data into a CSV record
-		record := []string{
-			title,
-			views,
-			release,
-			length,
-			link,
+	// Get the release date
+	if release := youtubeReleasePattern.FindStringSubmatchIndex(rest); release != nil {
+		video.PublishedText = rest[release[0]:release[1]]
+		if published, err := ParseYouTubeRelativeDate(video.PublishedText, reference); err == nil {
+			video.Published = published.Format(dateFormat)
 		}
+		rest = rest[:release[0]] + rest[release[1]:]
+	}
+
+	// Get the length, as "1:02:03" or "1 hour, 2 minutes, 3 seconds"
+	if clock := youtubeClockPattern.FindString(rest); clock != "" {
+		video.LengthText = clock
+	} else if durations := youtubeDurationPattern.FindAllStringIndex(rest, -1); durations != nil {
+		video.LengthText = strings.TrimSuffix(rest[durations[0][0]:durations[len(durations)-1][1]], ",")
+	}
+	if video.LengthText != "" {
+		video.Length, _ = ParseYouTubeLength(video.LengthText)
+	}
+
+	if video.Title == "" {
+		return video, fmt.Errorf("no title")
+	}
+	return video, nil
+}
+
+// Parse a view count such as "1,234", "1.2K", "3M" or "No"
+func ParseYouTubeViews(value string) (int64, error) {
+	value = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(value), " ", ""))
+	if value == "NO" {
+		return 0, nil
+	}
 
-		// Write the record to the CSV file
-		err := writer.Write(record)
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(value, "K"):
+		multiplier = 1e3
+	case strings.HasSuffix(value, "M"):
+		multiplier = 1e6
+	case strings.HasSuffix(value, "B"):
+		multiplier = 1e9
+	}
+	if multiplier > 1 {
+		number, err := strconv.ParseFloat(strings.ReplaceAll(value[:len(value)-1], ",", "."), 64)
 		if err != nil {
-			fmt.Println("❌ Error writing to CSV:", err)
-			return
+			return 0, fmt.Errorf("invalid view count: %s", value)
 		}
+		return int64(number*multiplier + 0.5), nil
+	}
+
+	number, err := strconv.ParseInt(strings.NewReplacer(",", "", ".", "").Replace(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid view count: %s", value)
 	}
-	if err := scanner.Err(); err != nil {
-		fmt.Println("❌ Error reading file:", err)
-		return
+	return number, nil
+}
+
+// Parse a video length such as "12:34", "1:02:03" or "1 hour, 2 minutes" to seconds
+func ParseYouTubeLength(value string) (int, error) {
+	if clock := youtubeClockPattern.FindStringSubmatch(value); clock != nil {
+		hours, _ := strconv.Atoi(clock[1])
+		minutes, _ := strconv.Atoi(clock[2])
+		seconds, _ := strconv.Atoi(clock[3])
+		return hours*3600 + minutes*60 + seconds, nil
 	}
 
-	fmt.Println("✅ CSV file created successfully.")
+	units := map[string]int{"hour": 3600, "minute": 60, "second": 1}
+	total := 0
+	matches := youtubeDurationPattern.FindAllStringSubmatch(value, -1)
+	if matches == nil {
+		return 0, fmt.Errorf("invalid length: %s", value)
+	}
+	for _, match := range matches {
+		number, _ := strconv.Atoi(match[1])
+		total += number * units[strings.ToLower(match[2])]
+	}
+	return total, nil
+}
+
+// Convert a relative date such as "3 weeks ago" to an approximate date before the reference time
+func ParseYouTubeRelativeDate(value string, reference time.Time) (time.Time, error) {
+	match := youtubeReleasePattern.FindStringSubmatch(value)
+	if match == nil {
+		return time.Time{}, fmt.Errorf("invalid relative date: %s", value)
+	}
+	number, _ := strconv.Atoi(match[1])
+
+	switch strings.ToLower(match[2]) {
+	case "second":
+		return reference.Add(-time.Duration(number) * time.Second), nil
+	case "minute":
+		return reference.Add(-time.Duration(number) * time.Minute), nil
+	case "hour":
+		return reference.Add(-time.Duration(number) * time.Hour), nil
+	case "day":
+		return reference.AddDate(0, 0, -number), nil
+	case "week":
+		return reference.AddDate(0, 0, -7*number), nil
+	case "month":
+		return reference.AddDate(0, -number, 0), nil
+	default:
+		return reference.AddDate(-number, 0, 0), nil
+	}
+}
+
+// Write videos as csv with a header or as json to a file, "-" writes to stdout
+func WriteYouTubeVideos(videos []YouTubeVideo, format string, output string) error {
+	var writer io.Writer = os.Stdout
+	if output != "-" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	switch format {
+	case "json":
+		if videos == nil {
+			videos = []YouTubeVideo{}
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(videos)
+	case "", "csv":
+		csvWriter := csv.NewWriter(writer)
+		csvWriter.Write([]string{"title", "id", "url", "views", "published", "published_text", "length", "length_text", "thumbnail"})
+		for _, video := range videos {
+			thumbnail := ""
+			if len(video.Thumbnails) > 0 {
+				thumbnail = video.Thumbnails[len(video.Thumbnails)-1]
+			}
+			csvWriter.Write([]string{video.Title, video.ID, video.URL, strconv.FormatInt(video.Views, 10), video.Published, video.PublishedText,
+				strconv.Itoa(video.Length), video.LengthText, thumbnail})
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	default:
+		return fmt.Errorf("unsupported format: %s (csv|json)", format)
+	}
 }
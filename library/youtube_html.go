@@ -0,0 +1,200 @@
+package library
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Start of the embedded initial data of a saved YouTube page
+var youtubeInitialDataPattern = regexp.MustCompile(`(?:var\s+ytInitialData|window\[["']ytInitialData["']\])\s*=\s*`)
+
+// Video renderers of channel, playlist, search and shorts pages
+var youtubeRenderers = []string{"videoRenderer", "gridVideoRenderer", "compactVideoRenderer", "playlistVideoRenderer", "playlistPanelVideoRenderer", "reelItemRenderer"}
+
+// Extract the videos of a saved channel or playlist HTML page from its ytInitialData
+func ExtractYouTubeHTML(inputFilePath string, reference time.Time) ([]YouTubeVideo, []YouTubeFailure, error) {
+	content, err := os.ReadFile(inputFilePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := YouTubeInitialData(string(content))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var videos []YouTubeVideo
+	var failures []YouTubeFailure
+	seen := make(map[string]bool)
+
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch value := node.(type) {
+		case map[string]interface{}:
+			for _, name := range youtubeRenderers {
+				renderer, ok := value[name].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				video, err := parseYouTubeRenderer(renderer, reference)
+				if err != nil {
+					failures = append(failures, YouTubeFailure{Line: len(videos) + len(failures) + 1, Reason: err.Error(), Text: name + " " + youtubeText(renderer["title"])})
+				} else if !seen[video.ID] {
+					seen[video.ID] = true
+					videos = append(videos, video)
+				}
+			}
+			// Sorted keys keep the order of the videos stable, the arrays keep the page order
+			keys := make([]string, 0, len(value))
+			for key := range value {
+				if !SliceContainsString(youtubeRenderers, key) {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				walk(value[key])
+			}
+		case []interface{}:
+			for _, child := range value {
+				walk(child)
+			}
+		}
+	}
+	walk(data)
+
+	return videos, failures, nil
+}
+
+// Decode the ytInitialData object embedded in a YouTube page
+func YouTubeInitialData(content string) (interface{}, error) {
+	location := youtubeInitialDataPattern.FindStringIndex(content)
+	if location == nil {
+		return nil, fmt.Errorf("no ytInitialData in the page")
+	}
+
+	// The object ends at its matching brace, braces inside strings are skipped
+	start := location[1]
+	if start >= len(content) || content[start] != '{' {
+		return nil, fmt.Errorf("ytInitialData is not an object")
+	}
+	depth, inString, escaped := 0, false, false
+	end := -1
+	for i := start; i < len(content) && end < 0; i++ {
+		c := content[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				end = i + 1
+			}
+		}
+	}
+	if end < 0 {
+		return nil, fmt.Errorf("ytInitialData is truncated")
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(content[start:end]), &data); err != nil {
+		return nil, fmt.Errorf("invalid ytInitialData: %v", err)
+	}
+	return data, nil
+}
+
+// Parse a video renderer, only the video id is required
+func parseYouTubeRenderer(renderer map[string]interface{}, reference time.Time) (YouTubeVideo, error) {
+	var video YouTubeVideo
+	video.ID, _ = renderer["videoId"].(string)
+	if video.ID == "" {
+		return video, fmt.Errorf("no video id")
+	}
+	video.URL = "https://www.youtube.com/watch?v=" + video.ID
+
+	video.Title = youtubeText(renderer["title"])
+	if video.Title == "" {
+		video.Title = youtubeText(renderer["headline"]) // Shorts
+	}
+
+	// Views, publish text and length, playlists combine views and publish text in videoInfo
+	viewsText := youtubeText(renderer["viewCountText"])
+	video.PublishedText = youtubeText(renderer["publishedTimeText"])
+	if info := youtubeRuns(renderer["videoInfo"]); len(info) > 0 {
+		for _, run := range info {
+			switch {
+			case youtubeViewsPattern.MatchString(run):
+				viewsText = run
+			case youtubeReleasePattern.MatchString(run):
+				video.PublishedText = run
+			}
+		}
+	}
+	if match := youtubeViewsPattern.FindStringSubmatch(viewsText); match != nil {
+		video.Views, _ = ParseYouTubeViews(match[1])
+	}
+	if video.PublishedText != "" {
+		if published, err := ParseYouTubeRelativeDate(video.PublishedText, reference); err == nil {
+			video.Published = published.Format(dateFormat)
+		}
+	}
+
+	video.LengthText = youtubeText(renderer["lengthText"])
+	if seconds, ok := renderer["lengthSeconds"].(string); ok {
+		video.Length, _ = strconv.Atoi(seconds)
+	} else if video.LengthText != "" {
+		video.Length, _ = ParseYouTubeLength(video.LengthText)
+	}
+
+	if thumbnail, ok := renderer["thumbnail"].(map[string]interface{}); ok {
+		thumbnails, _ := thumbnail["thumbnails"].([]interface{})
+		for _, item := range thumbnails {
+			if item, ok := item.(map[string]interface{}); ok {
+				if url, ok := item["url"].(string); ok {
+					video.Thumbnails = append(video.Thumbnails, url)
+				}
+			}
+		}
+	}
+	return video, nil
+}
+
+// Text of a YouTube text object, either simpleText or the joined runs
+func youtubeText(node interface{}) string {
+	if text, ok := node.(map[string]interface{}); ok {
+		if simple, ok := text["simpleText"].(string); ok {
+			return strings.TrimSpace(simple)
+		}
+	}
+	return strings.TrimSpace(strings.Join(youtubeRuns(node), ""))
+}
+
+// Runs of a YouTube text object
+func youtubeRuns(node interface{}) []string {
+	text, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	runs, _ := text["runs"].([]interface{})
+	var result []string
+	for _, run := range runs {
+		if run, ok := run.(map[string]interface{}); ok {
+			if value, ok := run["text"].(string); ok {
+				result = append(result, value)
+			}
+		}
+	}
+	return result
+}
@@ -25,6 +25,7 @@ func main() {
 	library.InitiatePHPCSFunction(flags)
 	library.InitiateQuoteFunction(flags)
 	library.InitiateRsyncFunction(flags)
+	library.InitiateSitemapFunction(flags)
 	library.InitiateSyncthingFunction(flags)
 	library.InitiateXMLFunction(flags)
 	library.InitiateYouTubeFunction(flags)
@@ -139,6 +139,9 @@ func CleanVendorDirandFilesforProduction(path string, BuildType string) {
 			})
 	}
 
+	/** Remove Directories Left Empty */
+	library.CleanPath(path+"/vendor/", library.CleanOptions{EmptyDirs: true})
+
 	fmt.Println("✅ Success Cleanup Vendor Directories and Files for Production")
 }
 
@@ -232,6 +235,12 @@ func SetConfigProduction(path string, production bool) {
 		return
 	}
 
+	// Validate against the WordPress config schema
+	if err := library.ValidateConfigFile(configPath, "wordpress-config"); err != nil {
+		fmt.Println("❌", err)
+		os.Exit(1)
+	}
+
 	// Get Content
 	content := library.ReadFile(configPath)
 